@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// defaultConfigFile returns the path of the default configuration file,
+// pdftitle/config.toml in the user configuration directory.
+func defaultConfigFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pdftitle", "config.toml")
+}
+
+// loadConfig sets the flags of set with the values of the configuration
+// file fname. The keys of the file are the names of the flags. Keys that
+// are not flags of set are ignored, they may be flags of other commands.
+// If optional is set, a missing file is not an error.
+func loadConfig(set *flag.FlagSet, fname string, optional bool) error {
+	var config map[string]any
+	if _, err := toml.DecodeFile(fname, &config); err != nil {
+		if optional && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for name, value := range config {
+		if set.Lookup(name) == nil {
+			continue
+		}
+		// arrays are the values of repeated flags, like dict.
+		values, ok := value.([]any)
+		if !ok {
+			values = []any{value}
+		}
+		for _, v := range values {
+			if err := set.Set(name, fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("%s: key %q: %v", fname, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// configArg returns the value of the config flag of set in args, before
+// parsing them, or the empty string if it is not set. Like FlagSet.Parse
+// it stops at the first argument that is not a flag.
+func configArg(set *flag.FlagSet, args []string) string {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if len(a) < 2 || a[0] != '-' || a == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(a[1:], "-"), "=")
+		f := set.Lookup(name)
+		if f == nil {
+			break
+		}
+		if name == "config" && hasValue {
+			return value
+		}
+		if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); hasValue || ok && bf.IsBoolFlag() {
+			continue
+		}
+		// the value is the next argument.
+		i++
+		if name == "config" && i < len(args) {
+			return args[i]
+		}
+	}
+	return ""
+}
+
+// envName returns the name of the environment variable of the flag
+// name, PDFTITLE_NAME, where NAME is name in upper case with dashes
+// replaced by underscores, like PDFTITLE_GS.
+func envName(name string) string {
+	return "PDFTITLE_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// loadEnv sets the flags of set with the values of their environment
+// variables, see envName. Like the flags of the command line they are
+// set flags, that profiles do not override.
+func loadEnv(set *flag.FlagSet) error {
+	var err error
+	set.VisitAll(func(f *flag.Flag) {
+		name := envName(f.Name)
+		if value, ok := os.LookupEnv(name); ok && err == nil {
+			if serr := set.Set(f.Name, value); serr != nil {
+				err = fmt.Errorf("%s: %v", name, serr)
+			}
+		}
+	})
+	return err
+}
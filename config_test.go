@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestConfigArg(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-config", "a.toml", "x.pdf"}, "a.toml"},
+		{[]string{"--config=a.toml", "x.pdf"}, "a.toml"},
+		{[]string{"-A", "-s", "0.2", "-config", "a.toml"}, "a.toml"},
+		{[]string{"-s", "-config", "x.pdf"}, ""},
+		{[]string{"x.pdf", "-config", "a.toml"}, ""},
+		{[]string{"--", "-config", "a.toml"}, ""},
+		{[]string{"-A"}, ""},
+	}
+	for _, tt := range tests {
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		addExtractorFlags(set)
+		if got := configArg(set, tt.args); got != tt.want {
+			t.Errorf("configArg(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestParseFlagsConfig(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "config.toml")
+	config := "s = 0.3\ndict = [\"a.txt\", \"de=b.txt\"]\n"
+	if err := os.WriteFile(fname, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		args    []string
+		spacing float64
+		dicts   []string
+	}{
+		{[]string{"-config", fname, "x.pdf"}, 0.3, []string{"a.txt", "de=b.txt"}},
+		{[]string{"-config", fname, "-s", "0.2", "x.pdf"}, 0.2, []string{"a.txt", "de=b.txt"}},
+		{[]string{"-config", fname, "-dict", "c.txt", "x.pdf"}, 0.3, []string{"c.txt"}},
+		{[]string{"-config", fname, "-dict", "c.txt", "-dict", "d.txt", "x.pdf"}, 0.3, []string{"c.txt", "d.txt"}},
+	}
+	for _, tt := range tests {
+		dictFiles = nil
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		addExtractorFlags(set)
+		parseFlags(set, tt.args)
+		if spacingCoefficient != tt.spacing || !slices.Equal(dictFiles, tt.dicts) {
+			t.Errorf("parseFlags(%q): spacing %v, dicts %q, want %v, %q", tt.args, spacingCoefficient, dictFiles, tt.spacing, tt.dicts)
+		}
+	}
+}
+
+func TestParseFlagsEnv(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(fname, []byte("s = 0.3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PDFTITLE_CONFIG", fname)
+	t.Setenv("PDFTITLE_PAGES", "2")
+	t.Setenv("PDFTITLE_DICT", "e.txt")
+	tests := []struct {
+		env     string
+		args    []string
+		spacing float64
+		dicts   []string
+	}{
+		{"", []string{"x.pdf"}, 0.3, []string{"e.txt"}},
+		{"0.25", []string{"x.pdf"}, 0.25, []string{"e.txt"}},
+		{"0.25", []string{"-s", "0.2", "-dict", "c.txt", "x.pdf"}, 0.2, []string{"c.txt"}},
+	}
+	for _, tt := range tests {
+		if tt.env != "" {
+			t.Setenv("PDFTITLE_S", tt.env)
+		}
+		dictFiles = nil
+		setFlags = make(map[string]bool)
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		addExtractorFlags(set)
+		parseFlags(set, tt.args)
+		if spacingCoefficient != tt.spacing || !slices.Equal(dictFiles, tt.dicts) {
+			t.Errorf("parseFlags(%q) with PDFTITLE_S=%q: spacing %v, dicts %q, want %v, %q", tt.args, tt.env, spacingCoefficient, dictFiles, tt.spacing, tt.dicts)
+		}
+		// the flags of the environment are set, like those of args.
+		if pages != 2 || !setFlags["pages"] {
+			t.Errorf("parseFlags(%q): pages %v, set %v, want 2 of PDFTITLE_PAGES", tt.args, pages, setFlags["pages"])
+		}
+	}
+}
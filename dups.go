@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// normalizedTitle returns tl in lower case with only letters
+// and digits, for comparing titles.
+func normalizedTitle(tl string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(tl), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// similarTitles returns true if the normalized titles a and b have
+// almost the same words, as measured by their Jaccard index.
+func similarTitles(a, b string) bool {
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	set := make(map[string]int)
+	for _, w := range wa {
+		set[w] |= 1
+	}
+	for _, w := range wb {
+		set[w] |= 2
+	}
+	common := 0
+	for _, v := range set {
+		if v == 3 {
+			common++
+		}
+	}
+	return len(set) > 0 && float64(common)/float64(len(set)) >= 0.8
+}
+
+// duplicates groups the files with identical or near identical
+// titles. Files without titles are ignored.
+func duplicates(results []fileResult) [][]fileResult {
+	byTitle := make(map[string][]fileResult)
+	var keys []string
+	for _, f := range results {
+		key := normalizedTitle(f.Title)
+		if key == "" {
+			continue
+		}
+		if _, ok := byTitle[key]; !ok {
+			keys = append(keys, key)
+		}
+		byTitle[key] = append(byTitle[key], f)
+	}
+
+	// union similar titles, the root of a group is its first title.
+	parent := make([]int, len(keys))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range keys {
+		for j := i + 1; j < len(keys); j++ {
+			if similarTitles(keys[i], keys[j]) {
+				ri, rj := find(i), find(j)
+				parent[max(ri, rj)] = min(ri, rj)
+			}
+		}
+	}
+
+	groups := make(map[int][]fileResult)
+	for i, key := range keys {
+		r := find(i)
+		groups[r] = append(groups[r], byTitle[key]...)
+	}
+	var dups [][]fileResult
+	for i := range keys {
+		if g := groups[i]; len(g) > 1 {
+			dups = append(dups, g)
+		}
+	}
+	return dups
+}
+
+// printDuplicates prints to w each group of duplicates as the title
+// of its first file followed by the files, one per line, indented
+// by a tab. Groups are separated by blank lines.
+func printDuplicates(w io.Writer, dups [][]fileResult) {
+	for i, g := range dups {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, g[0].Title)
+		for _, f := range g {
+			fmt.Fprintf(w, "\t%s\n", f.Path)
+		}
+	}
+}
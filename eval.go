@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/anastasop/pdftitle/title"
+)
+
+// runEval is the eval command. It compares the extracted titles
+// with the expected titles of a list of files.
+func runEval(args []string) {
+	set := flag.NewFlagSet("eval", flag.ExitOnError)
+	addExtractorFlags(set)
+	set.Usage = commandUsage(set, "eval [flags] expected.csv", `Eval extracts the titles of the files listed in a csv file with
+rows of path and expected title, prints the files whose title
+differs and the fractions of titles that match exactly and that
+match ignoring case, punctuation and spacing.`)
+	parseFlags(set, args)
+	if set.NArg() != 1 {
+		set.Usage()
+	}
+
+	f, err := os.Open(set.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	rows, err := csv.NewReader(f).ReadAll()
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	extractor := newExtractor()
+	total, exact, normalized, failed := 0, 0, 0, 0
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		path, expected := row[0], row[1]
+		tl, err := extractor.Extract(path)
+		if err != nil && !errors.Is(err, title.ErrNoText) {
+			fmt.Fprintf(os.Stderr, "error: %s: %v\n", path, err)
+			failed++
+		}
+		total++
+		switch {
+		case tl == expected:
+			exact++
+			normalized++
+		case tl != "" && normalizedTitle(tl) == normalizedTitle(expected):
+			normalized++
+			fmt.Printf("%s: got %q, want %q, differ in case or punctuation\n", path, tl, expected)
+		default:
+			fmt.Printf("%s: got %q, want %q\n", path, tl, expected)
+		}
+	}
+	if total == 0 {
+		fmt.Println("no files")
+		return
+	}
+	fmt.Printf("exact: %d/%d (%s)\n", exact, total, percent(exact, total))
+	fmt.Printf("normalized: %d/%d (%s)\n", normalized, total, percent(normalized, total))
+	if failed > 0 {
+		fmt.Printf("errors: %d/%d (%s)\n", failed, total, percent(failed, total))
+	}
+}
+
+// percent returns n of total as a percentage.
+func percent(n, total int) string {
+	return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(total))
+}
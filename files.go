@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// inputFiles returns the files of the arguments followed
+// by the files listed in filesFrom. If recursive is set,
+// directories are replaced by the pdf files under them.
+func inputFiles(args []string, filesFrom string, recursive bool) ([]string, error) {
+	files, err := listedFiles(args, filesFrom)
+	if err != nil || !recursive {
+		return files, err
+	}
+
+	var expanded []string
+	for _, f := range files {
+		if fi, err := os.Stat(f); err != nil || !fi.IsDir() {
+			expanded = append(expanded, f)
+			continue
+		}
+		err := filepath.WalkDir(f, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".pdf") {
+				expanded = append(expanded, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
+// listedFiles returns args, with glob patterns expanded,
+// followed by the files listed in filesFrom.
+func listedFiles(args []string, filesFrom string) ([]string, error) {
+	files := expandGlobs(args)
+	if filesFrom == "" {
+		return files, nil
+	}
+
+	var r io.Reader = os.Stdin
+	if filesFrom != "-" {
+		f, err := os.Open(filesFrom)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 64*1024)
+	for sc.Scan() {
+		if line := sc.Text(); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, sc.Err()
+}
+
+// expandGlobs replaces the glob patterns of args with the files they
+// match. Shells on windows do not expand patterns. Arguments that name
+// existing files or match nothing are kept as is.
+func expandGlobs(args []string) []string {
+	var files []string
+	for _, arg := range args {
+		if !strings.ContainsAny(arg, "*?[") {
+			files = append(files, arg)
+			continue
+		}
+		if _, err := os.Stat(arg); err == nil {
+			files = append(files, arg)
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		if err != nil || len(matches) == 0 {
+			files = append(files, arg)
+			continue
+		}
+		files = append(files, matches...)
+	}
+	return files
+}
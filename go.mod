@@ -3,6 +3,8 @@ module github.com/anastasop/pdftitle
 go 1.23.5
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/caneroj1/stemmer v0.0.0-20170128035808-c9f2ce1504d5
+	golang.org/x/text v0.28.0
 	rsc.io/pdf v0.1.1
 )
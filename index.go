@@ -0,0 +1,87 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/anastasop/pdftitle/title"
+)
+
+// runIndex is the index command. It prints a table with the
+// titles of all pdf files under directories.
+func runIndex(args []string) {
+	set := flag.NewFlagSet("index", flag.ExitOnError)
+	addExtractorFlags(set)
+	workers := set.Int("j", 0, "extract the titles of `n` files concurrently (default the number of CPUs)")
+	output := set.String("o", "", "write the index to `file`, replaced atomically at the end, instead of stdout")
+	set.Usage = commandUsage(set, "index [flags] dir|file..", `Index extracts the titles of the pdf files under the directories,
+recursively, and prints a tab separated table with the path and
+the title of each file, sorted by path, for searching with grep.`)
+	parseFlags(set, args)
+	if set.NArg() == 0 {
+		set.Usage()
+	}
+
+	files, err := inputFiles(set.Args(), "", true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	type entry struct{ path, title string }
+	var entries []entry
+	err = newExtractor().Batch(context.Background(), slices.Values(files), *workers, func(path string, res title.Result, err error) {
+		if err == nil || errors.Is(err, title.ErrNoText) {
+			entries = append(entries, entry{path, res.Title})
+		}
+	})
+	failed := false
+	for _, err := range unjoin(err) {
+		if !errors.Is(err, title.ErrNoText) {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			failed = true
+		}
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return cmp.Compare(a.path, b.path)
+	})
+
+	var out io.Writer = os.Stdout
+	var outFile *atomicFile
+	if *output != "" {
+		if outFile, err = createAtomic(*output); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		out = outFile
+	}
+	for _, e := range entries {
+		fmt.Fprintf(out, "%s\t%s\n", e.path, e.title)
+	}
+	if outFile != nil {
+		if err := outFile.commit(); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// unjoin returns the errors joined in err by errors.Join.
+func unjoin(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		return j.Unwrap()
+	}
+	return []error{err}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/anastasop/pdftitle/title"
+)
+
+// chooseTitle shows to w the title tl of fname and the top ranked
+// candidates and reads the choice of the user from in. The user
+// picks a choice by its number, types a title or accepts the first
+// choice with an empty line.
+func chooseTitle(in *bufio.Reader, w io.Writer, fname, tl string, cands []title.Candidate) (string, error) {
+	choices := []string{}
+	if tl != "" {
+		choices = append(choices, tl)
+	}
+	for _, c := range cands {
+		if len(choices) == 5 {
+			break
+		}
+		if c.Text != "" && !slices.Contains(choices, c.Text) {
+			choices = append(choices, c.Text)
+		}
+	}
+
+	fmt.Fprintf(w, "%s\n", fname)
+	for i, c := range choices {
+		fmt.Fprintf(w, "  %d) %s\n", i+1, c)
+	}
+	fmt.Fprint(w, "choice number or title [1]: ")
+
+	line, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		if len(choices) == 0 {
+			return "", nil
+		}
+		return choices[0], nil
+	}
+	if n, err := strconv.Atoi(line); err == nil && n >= 1 && n <= len(choices) {
+		return choices[n-1], nil
+	}
+	return line, nil
+}
@@ -1,25 +1,26 @@
 package main
 
 import (
-	"bytes"
+	"bufio"
 	"cmp"
 	"context"
-	_ "embed"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
-	"math"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
 	"os/exec"
-	"regexp"
 	"slices"
+	"strconv"
 	"strings"
-	"time"
-	"unicode"
-	"unicode/utf8"
+	"text/template"
 
-	"github.com/caneroj1/stemmer"
-	"rsc.io/pdf"
+	"github.com/anastasop/pdftitle/title"
+	"golang.org/x/text/language"
 )
 
 var (
@@ -37,22 +38,214 @@ var (
 	// gsCmd points to the ghoscript executable.
 	gsCmd string
 
-	// wordsList is a list of words per line.
-	// It currently uses the ones from NetBSD dict 
-	//go:embed words
-	wordsList string
+	// hunspellDicts are the dictionaries of hunspell, comma separated,
+	// for checking titles with hunspell instead of the embedded dictionary.
+	hunspellDicts string
 
-	// words is wordsList as a set.
-	words map[string]bool = make(map[string]bool)
+	// ngramCheck toggles checking titles with the letter model
+	// instead of the embedded dictionary.
+	ngramCheck bool
 
-	// wordsExtractor is used to extract words from strings.
-	wordsExtractor = regexp.MustCompile("[[:alpha:]]{3,30}")
+	// locale is the language of the titles.
+	locale language.Tag
+
+	// likelyAcronyms toggles counting all caps words as dictionary words.
+	likelyAcronyms bool
+
+	// techWords toggles counting technical terms as dictionary words.
+	techWords bool
+
+	// stripQuotes toggles removing quotation marks enclosing the title.
+	stripQuotes bool
+
+	// subtitleSep separates the subtitle appended to the title.
+	subtitleSep string
+
+	// showAuthors toggles extracting and printing the authors.
+	showAuthors bool
+
+	// showAbstract toggles extracting and printing the abstract.
+	showAbstract bool
+
+	// showIDs toggles extracting the identifiers of documents.
+	showIDs bool
+
+	// showYear toggles extracting the publication year.
+	showYear bool
+
+	// showVenue toggles extracting the journal or conference name.
+	showVenue bool
+
+	// showKeywords toggles extracting the keywords.
+	showKeywords bool
+
+	// titleCase toggles converting all caps titles to title case.
+	titleCase bool
+
+	// textCase is the case of titles: preserve, title or sentence.
+	textCase string
+
+	// profile is the profile of documents: paper, slides, book, report or auto.
+	profile string
+
+	// useDests toggles the fallback to link and destination labels.
+	useDests bool
+
+	// useInfo toggles the title of the document Info dictionary as a candidate.
+	useInfo bool
+
+	// skipCovers toggles skipping covers and blank pages.
+	skipCovers bool
+
+	// margin is the height of the margins of pages without titles.
+	margin float64
+
+	// weights are the weights of the features in the scores of candidates.
+	weights title.Weights
+
+	// useXMP toggles the dc:title of XMP metadata as a candidate.
+	useXMP bool
+
+	// useOutline toggles the title of the outline as a candidate.
+	useOutline bool
+
+	// useTags toggles preferring the title tagged in the structure tree.
+	useTags bool
+
+	// repeatHeaderStrip toggles excluding running headers from titles.
+	repeatHeaderStrip bool
+
+	// repeatHeaderTitle toggles preferring running headers as titles.
+	repeatHeaderTitle bool
+
+	// pageNum is the page of the title. If 0, the first page.
+	pageNum int
+
+	// pages is the number of pages whose phrases are title candidates.
+	pages int
+
+	// minWords is the minimum number of words of a title.
+	minWords int
+
+	// maxLen is the maximum length of a title. If 0, titles
+	// are not truncated.
+	maxLen int
+
+	// perDocument toggles reporting a title for each document
+	// bundled in a pdf instead of a single title.
+	perDocument bool
+
+	// tuneSpacing toggles printing the titles extracted with
+	// a range of spacing coefficients.
+	tuneSpacing bool
+
+	// nullSep toggles NUL as field separator and record terminator
+	// for the output.
+	nullSep bool
+
+	// acronymsFile is a file with acronyms, one per line.
+	acronymsFile string
+
+	// dictFiles are files with words, one per line, for the dictionary,
+	// prefixed by the language of the words and =, like de=words.txt,
+	// for the dictionary of a language.
+	dictFiles []string
+
+	// modelFile is a file with a model written by the train command.
+	modelFile string
+
+	// blacklistFile is a file with phrases that are never titles, one per line.
+	blacklistFile string
+
+	// showPosition toggles printing the position of the title
+	// on the page.
+	showPosition bool
+
+	// jsonOutput toggles printing a json array with
+	// a record for each file.
+	jsonOutput bool
+
+	// ndjsonOutput toggles printing a json record for each file
+	// on a single line as soon as it is ready.
+	ndjsonOutput bool
+
+	// outputFormat is the format of the records of the files,
+	// csv or tsv. If empty, the records are printed as lines.
+	outputFormat string
+
+	// outputTemplate is a text/template for the result of each file.
+	outputTemplate string
+
+	// quiet toggles printing only the titles, without the filenames.
+	quiet bool
+
+	// filesFrom is a file with the paths of files, one per line.
+	// If "-", the paths are read from stdin.
+	filesFrom string
+
+	// recursive toggles processing the pdf files
+	// under the directories of the arguments.
+	recursive bool
+
+	// showProgress toggles reporting the progress of
+	// the extraction to stderr.
+	showProgress bool
+
+	// configFile is the configuration file with the defaults of flags.
+	configFile string
+
+	// setFlags are the names of the flags set on the command line,
+	// in the configuration file or in the environment.
+	setFlags = make(map[string]bool)
+
+	// showSummary toggles printing the counts of the outcomes
+	// of the files to stderr at the end.
+	showSummary bool
+
+	// outputFile is the file for the output instead of stdout.
+	// It is replaced atomically when the run completes.
+	outputFile string
+
+	// topN is the number of the best candidates to print
+	// with their scores instead of the title.
+	topN int
+
+	// findDups toggles printing groups of files with the same
+	// or almost the same title instead of the titles.
+	findDups bool
+
+	// sortBy is the order of the results: title, path or
+	// confidence. If empty, the order of the files.
+	sortBy string
+
+	// interactive toggles asking the user to choose the title
+	// among the top candidates.
+	interactive bool
+
+	// verbose toggles logging the decisions of the extractor.
+	verbose bool
+
+	// veryVerbose is like verbose but also logs all phrases.
+	veryVerbose bool
+
+	// candidatesFormat is the format for printing all phrases
+	// with their features instead of the title. Only json is supported.
+	candidatesFormat string
 )
 
 func usage() {
-	fmt.Fprint(os.Stderr, `usage: pdftitle file..
+	fmt.Fprint(os.Stderr, `usage: pdftitle [extract] [flags] file..
+       pdftitle rename|serve|index|eval|train [flags] ...
 
 Pdftitle prints the title of each pdf file.
+It prints a line "file: title" for each file, or with -null
+the file and the title each terminated by a NUL byte.
+
+The exit status is 0 if all files have a title, 1 if the extraction
+failed for some file, 2 for usage errors and 3 if some file has no title.
+
+The commands rename, serve, index, eval and train have their own flags,
+see pdftitle <command> -h.
 
 Flags:
 `)
@@ -61,269 +254,659 @@ Flags:
 }
 
 func main() {
-	flag.Float64Var(&spacingCoefficient, "s", 0.16, "spacing coefficient used to decided word boundaries")
-	flag.BoolVar(&disableWordsCheck, "w", false, "disable dictionary check")
-	flag.Float64Var(&wordsInDictPercent, "p", 0.20, "minimum percentage of words in dictionary for a valid title")
-	flag.StringVar(&gsCmd, "gs", "gs", "ghostscript exec")
-	flag.Usage = usage
-	flag.Parse()
+	args := os.Args[1:]
+	if len(args) > 0 {
+		switch args[0] {
+		case "extract":
+			runExtract(args[1:])
+			return
+		case "rename":
+			runRename(args[1:])
+			return
+		case "serve":
+			runServe(args[1:])
+			return
+		case "index":
+			runIndex(args[1:])
+			return
+		case "eval":
+			runEval(args[1:])
+			return
+		case "train":
+			runTrain(args[1:])
+			return
+		}
+	}
+	runExtract(args)
+}
 
-	if !disableWordsCheck {
-		for w := range strings.Lines(wordsList) {
-			words[strings.ToLower(strings.TrimRight(w, "\n"))] = true
+// addExtractorFlags defines in set the flags that configure the extractor.
+func addExtractorFlags(set *flag.FlagSet) {
+	set.Float64Var(&spacingCoefficient, "s", 0.16, "spacing coefficient used to decided word boundaries")
+	set.Float64Var(&spacingCoefficient, "spacing", 0.16, "same as -s")
+	set.BoolVar(&disableWordsCheck, "w", false, "disable dictionary check")
+	set.Float64Var(&wordsInDictPercent, "p", 0.20, "minimum percentage of words in dictionary for a valid title")
+	set.Float64Var(&wordsInDictPercent, "threshold", 0.20, "same as -p")
+	set.StringVar(&gsCmd, "gs", "gs", "ghostscript exec")
+	set.StringVar(&hunspellDicts, "hunspell", "", "check the words of titles with hunspell and the comma separated `dicts`, like en_US,de_DE, instead of the embedded dictionary")
+	set.BoolVar(&ngramCheck, "ngram", false, "check titles with a character n-gram model of words, which accepts proper nouns and new words spelled like words, instead of the embedded dictionary")
+	set.TextVar(&locale, "locale", language.Und, "BCP 47 language `tag` of titles")
+	set.Func("dict", "`file` with words, one per line, that count as dictionary words, or lang=file for the dictionary of language lang, like de=words.txt; may be repeated", func(s string) error {
+		if lang, _, ok := strings.Cut(s, "="); ok {
+			if _, err := language.Parse(lang); err != nil {
+				return fmt.Errorf("bad language %q: %v", lang, err)
+			}
 		}
+		dictFiles = append(dictFiles, s)
+		return nil
+	})
+	set.StringVar(&acronymsFile, "acronyms", "", "`file` with acronyms, one per line, that count as dictionary words")
+	set.StringVar(&blacklistFile, "blacklist", "", "`file` with phrases, one per line, that are never titles, like boilerplate of sites, with * at the end to match prefixes")
+	set.StringVar(&modelFile, "model", "", "rank candidates with the model of `file`, written by pdftitle train, instead of the weights")
+	set.BoolVar(&likelyAcronyms, "A", false, "count all caps words of 2 to 6 letters as dictionary words")
+	set.BoolVar(&techWords, "tech", false, "count technical and scientific terms, like Kubernetes or genomics, as dictionary words")
+	set.BoolVar(&stripQuotes, "strip-quotes", false, "remove quotation marks enclosing the title")
+	set.BoolVar(&skipCovers, "skip-covers", true, "skip blank pages and covers with only images or a few words to find the title page")
+	set.Float64Var(&margin, "margin", 0.05, "exclude text in the top and bottom `fraction` of pages, like running headers and footers, from titles")
+	set.TextVar(&weights, "weights", title.DefaultWeights, "`weights` of font size, position, centering, boldness, dictionary words and length in the scores of candidates")
+	set.StringVar(&subtitleSep, "subtitle", "", "append the subtitle, the phrase just below the title in a smaller font, separated by `sep`, like ': '")
+	set.BoolVar(&showAuthors, "authors", false, "also extract the authors from the lines below the title and print them after the title")
+	set.BoolVar(&showIDs, "ids", false, "also extract identifiers, the DOI and arXiv id of the title page and the ISBNs of the first pages, for the -json records")
+	set.BoolVar(&showYear, "year", false, "also extract the publication year, for the -json records and -f templates like '{{.Title}} ({{.Year}})'")
+	set.BoolVar(&showVenue, "venue", false, "also extract the journal or conference name of the title page, for the -json records")
+	set.BoolVar(&showKeywords, "keywords", false, "also extract the keywords of the Keywords or Index Terms line, for the -json records")
+	set.BoolVar(&showAbstract, "abstract", false, "also extract the abstract and print it, indented by a tab, on the line after the title")
+	set.BoolVar(&titleCase, "titlecase", false, "convert titles in all caps to title case, keeping acronyms")
+	set.Func("profile", "set the thresholds for `type` paper, slides, book or report documents, or auto to detect it", func(s string) error {
+		if !title.IsProfile(s) {
+			return fmt.Errorf("unsupported profile %q", s)
+		}
+		profile = s
+		return nil
+	})
+	set.Func("case", "convert titles to `case` preserve, title or sentence, keeping acronyms", func(s string) error {
+		switch s {
+		case title.CasePreserve, title.CaseTitle, title.CaseSentence:
+			textCase = s
+			return nil
+		}
+		return fmt.Errorf("unsupported case %q", s)
+	})
+	set.BoolVar(&useInfo, "info", false, "consider the title of the document Info dictionary as a title candidate")
+	set.BoolVar(&useXMP, "xmp", false, "consider the dc:title of the XMP metadata as a title candidate")
+	set.BoolVar(&useTags, "tags", false, "prefer the text tagged Title or H1 in the structure tree of tagged pdfs as the title")
+	set.BoolVar(&useOutline, "outline", false, "consider the first bookmark of the outline as a title candidate")
+	set.BoolVar(&useDests, "dests", false, "fallback to labels of links and named destinations for the title")
+	set.BoolVar(&repeatHeaderStrip, "repeat-header-strip", false, "exclude running headers from titles")
+	set.BoolVar(&repeatHeaderTitle, "repeat-header-title", false, "prefer running headers as titles")
+	set.IntVar(&pageNum, "page", 0, "extract the title from page `N` instead of the first page")
+	set.IntVar(&pages, "pages", 1, "consider the phrases of the first `N` pages, from the title page, as title candidates, unless the page is set with -page")
+	set.IntVar(&maxLen, "maxlen", 80, "maximum length of titles in bytes, cut at a word boundary, or 0 for no limit")
+	set.IntVar(&minWords, "minwords", 0, "minimum number of words of at least 3 letters in a title")
+	set.StringVar(&configFile, "config", "", "read the defaults of flags from the toml `file` (default pdftitle/config.toml in the user config directory)")
+	set.BoolVar(&verbose, "v", false, "log the top phrases, their features and why candidates were rejected to stderr")
+	set.BoolVar(&veryVerbose, "vv", false, "like -v but log all phrases")
+}
+
+// parseFlags parses args with set. The configuration file and the
+// environment set the defaults of the flags.
+func parseFlags(set *flag.FlagSet, args []string) {
+	// the configuration and the environment set the flags
+	// before parsing args, for the flags to take precedence.
+	configFile = cmp.Or(configArg(set, args), os.Getenv(envName("config")))
+	if err := applyConfig(set); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+	if err := loadEnv(set); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+
+	// -dict flags of args replace those of the configuration.
+	defaultDicts := dictFiles
+	dictFiles = nil
+	set.Parse(args)
+	if dictFiles == nil {
+		dictFiles = defaultDicts
 	}
+	set.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+}
 
-	for _, fname := range flag.Args() {
-		tl, err := title(fname)
-		if err == nil {
-			fmt.Fprintf(os.Stdout, "%s: %s\n", fname, tl)
+// newExtractor returns an extractor configured with the flags
+// of addExtractorFlags.
+func newExtractor() *title.Extractor {
+	opts := []title.Option{
+		title.WithDictCheck(!disableWordsCheck),
+		title.WithGhostscript(gsCmd),
+		title.WithLocale(locale),
+		title.WithLikelyAcronyms(likelyAcronyms),
+		title.WithTechWords(techWords),
+		title.WithStripQuotes(stripQuotes),
+		title.WithTitleCase(titleCase),
+		title.WithCase(textCase),
+		title.WithProfile(profile),
+		title.WithAuthors(showAuthors),
+		title.WithAbstract(showAbstract),
+		title.WithIdentifiers(showIDs),
+		title.WithYear(showYear),
+		title.WithVenue(showVenue),
+		title.WithKeywords(showKeywords),
+		title.WithSubtitle(subtitleSep),
+		title.WithDests(useDests),
+		title.WithInfo(useInfo),
+		title.WithXMP(useXMP),
+		title.WithOutline(useOutline),
+		title.WithTags(useTags),
+		title.WithWeights(weights),
+		title.WithMargin(margin),
+		title.WithSkipCovers(skipCovers),
+		title.WithRepeatHeaderStrip(repeatHeaderStrip),
+		title.WithRepeatHeaderTitle(repeatHeaderTitle),
+		title.WithPage(pageNum),
+		title.WithMinWords(minWords),
+		title.WithMaxLen(maxLen),
+	}
+	// the thresholds of -profile are those not set with flags.
+	if setFlags["s"] || setFlags["spacing"] {
+		opts = append(opts, title.WithSpacing(spacingCoefficient))
+	}
+	if setFlags["p"] || setFlags["threshold"] {
+		opts = append(opts, title.WithDictThreshold(wordsInDictPercent))
+	}
+	if setFlags["pages"] {
+		opts = append(opts, title.WithPages(pages))
+	}
+	if verbose || veryVerbose {
+		level := slog.LevelDebug
+		if veryVerbose {
+			level = title.LevelTrace
+		}
+		opts = append(opts, title.WithLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))))
+	}
+	if acronymsFile != "" {
+		data, err := os.ReadFile(acronymsFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, title.WithAcronyms(strings.Fields(string(data))...))
+	}
+	for _, fname := range dictFiles {
+		lang, file, ok := strings.Cut(fname, "=")
+		if !ok {
+			file = fname
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		if ok {
+			opts = append(opts, title.WithDictionary(language.Make(lang), strings.Fields(string(data))...))
 		} else {
-			fmt.Fprintf(os.Stderr, "error: %s: %v\n", fname, err)
+			opts = append(opts, title.WithWords(strings.Fields(string(data))...))
+		}
+	}
+	if blacklistFile != "" {
+		data, err := os.ReadFile(blacklistFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, title.WithBlacklist(strings.Split(string(data), "\n")...))
+	}
+	if ngramCheck && hunspellDicts != "" {
+		fmt.Fprintln(os.Stderr, "-ngram and -hunspell both replace the dictionary check and cannot be used together")
+		os.Exit(2)
+	}
+	if ngramCheck {
+		opts = append(opts, title.WithValidator(title.NgramModel{}))
+	}
+	if hunspellDicts != "" {
+		cmd, err := exec.LookPath("hunspell")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		h := title.Hunspell{
+			Cmd:       cmd,
+			Dicts:     strings.Split(hunspellDicts, ","),
+			Threshold: wordsInDictPercent,
+		}
+		// fail early if the dictionaries are not installed.
+		if _, err := h.Check("title"); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
 		}
+		opts = append(opts, title.WithValidator(h))
 	}
+	if modelFile != "" {
+		f, err := os.Open(modelFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		model, err := title.ReadModel(f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s: %v\n", modelFile, err)
+			os.Exit(1)
+		}
+		opts = append(opts, title.WithScorer(model))
+	}
+	return title.New(opts...)
 }
 
-// title tries to extract the pdf title of file.
-func title(fname string) (string, error) {
-	phrases, err := phrasesOfDoc(func() (*pdf.Reader, error) {
-		return pdf.Open(fname)
-	})
-	if err == nil {
-		return titleFromPhrases(phrases), nil
+// runExtract is the extract command, the default command.
+func runExtract(args []string) {
+	addExtractorFlags(flag.CommandLine)
+	flag.BoolVar(&perDocument, "per-document", false, "print the title and starting page of each bundled document as json")
+	flag.BoolVar(&tuneSpacing, "tune", false, "print the titles extracted with a range of spacing coefficients")
+	flag.BoolVar(&nullSep, "null", false, "terminate filenames and titles with NUL instead of \": \" and newline")
+	flag.BoolVar(&nullSep, "print0", false, "same as -null")
+	flag.BoolVar(&showPosition, "position", false, "print the title and its position on the page as json")
+	flag.BoolVar(&jsonOutput, "json", false, "print a json array with the path, title, confidence, error and timing of each file")
+	flag.BoolVar(&ndjsonOutput, "ndjson", false, "like -json but print each record on a single line as soon as it is ready")
+	flag.StringVar(&outputFormat, "format", "", "print the file, title, status, error and confidence of each file in `format` csv or tsv")
+	flag.StringVar(&outputTemplate, "f", "", "print the result of each file with the text/template `tmpl`, like '{{.Title}} — {{.Path}}'")
+	flag.BoolVar(&quiet, "q", false, "print only the titles, without the filenames")
+	flag.StringVar(&filesFrom, "files-from", "", "read the paths of files, one per line, from `file`, or stdin if -")
+	flag.BoolVar(&recursive, "r", false, "process the pdf files under directories recursively")
+	flag.BoolVar(&showProgress, "progress", false, "report the count of files done, the current file and the ETA to stderr")
+	flag.BoolVar(&showSummary, "summary", false, "print the counts of processed, failed and untitled files, ghostscript fallbacks and the total time to stderr at the end")
+	flag.StringVar(&outputFile, "o", "", "write the output to `file`, replaced atomically at the end, instead of stdout")
+	flag.IntVar(&topN, "n", 0, "print the `N` best title candidates of each file with their scores instead of the title")
+	flag.BoolVar(&findDups, "dups", false, "print groups of files with the same or almost the same title instead of the titles")
+	flag.StringVar(&sortBy, "sort", "", "print the results sorted by `key` title, path or confidence (decreasing) after all files are done")
+	flag.BoolVar(&interactive, "i", false, "show the top candidate titles of each file on stderr and read the choice, or a title, from stdin")
+	flag.StringVar(&candidatesFormat, "candidates", "", "print all phrases with their features in `format` json instead of the title")
+	flag.Usage = usage
+	parseFlags(flag.CommandLine, args)
+	if flag.NArg() == 0 && filesFrom == "" {
+		usage()
 	}
 
-	// the pdf package cannot read zipped deflated encoded pdf
-	// so we use gs to convert.
-	if !strings.Contains(err.Error(), "stream not present") {
-		return "", err
+	if candidatesFormat != "" && candidatesFormat != "json" {
+		fmt.Fprintf(os.Stderr, "unsupported candidates format %q\n", candidatesFormat)
+		usage()
+	}
+	if showPosition && (quiet || nullSep) {
+		fmt.Fprintln(os.Stderr, "-position prints json and cannot be used with -q or -null")
+		usage()
+	}
+	if interactive && filesFrom == "-" {
+		fmt.Fprintln(os.Stderr, "-i reads the choices from stdin and cannot be used with -files-from -")
+		usage()
+	}
+	if sortBy != "" && sortBy != "title" && sortBy != "path" && sortBy != "confidence" {
+		fmt.Fprintf(os.Stderr, "unsupported sort key %q\n", sortBy)
+		usage()
+	}
+	if outputFormat != "" && outputFormat != "csv" && outputFormat != "tsv" {
+		fmt.Fprintf(os.Stderr, "unsupported format %q\n", outputFormat)
+		usage()
+	}
+	var tmpl *template.Template
+	if outputTemplate != "" {
+		var err error
+		if tmpl, err = template.New("f").Parse(outputTemplate); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid template: %v\n", err)
+			usage()
+		}
 	}
-	pdfdec, err := decodedWithGhostscript(fname)
+
+	extractor := newExtractor()
+
+	files, err := inputFiles(flag.Args(), filesFrom, recursive)
 	if err != nil {
-		return "", err
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
 	}
 
-	phrases, err = phrasesOfDoc(func() (*pdf.Reader, error) {
-		return pdf.NewReader(bytes.NewReader(pdfdec.Bytes()), int64(pdfdec.Len()))
-	})
-	if err == nil {
-		return titleFromPhrases(phrases), nil
+	var out io.Writer = os.Stdout
+	var outFile *atomicFile
+	if outputFile != "" {
+		if outFile, err = createAtomic(outputFile); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		out = outFile
 	}
 
-	return "", err
-}
+	stdin := bufio.NewReader(os.Stdin)
+
+	// the summary also decides the exit status.
+	sum := newSummary()
+	records := []record{}
+	var table *csv.Writer
+	if outputFormat != "" {
+		table = csv.NewWriter(out)
+		if outputFormat == "tsv" {
+			table.Comma = '\t'
+		}
+		table.Write([]string{"file", "title", "status", "error", "confidence"})
+	}
+	var prog *progress
+	if showProgress {
+		prog = newProgress(os.Stderr, len(files))
+	}
+	// emit prints the result of a file in the output format.
+	emit := func(f fileResult) {
+		failed := f.Error != ""
+		switch {
+		case jsonOutput:
+			records = append(records, f.record())
+		case tmpl != nil:
+			if err := tmpl.Execute(out, f); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %s: %v\n", f.Path, err)
+				failed = true
+			}
+			fmt.Fprintln(out)
+		case table != nil:
+			r := f.record()
+			table.Write([]string{r.Path, r.Title, r.status(), r.Error, strconv.FormatFloat(r.Confidence, 'f', 3, 64)})
+		case ndjsonOutput:
+			json.NewEncoder(out).Encode(f.record())
+		case failed:
+			fmt.Fprintf(os.Stderr, "error: %s: %s\n", f.Path, f.Error)
+		case showPosition:
+			json.NewEncoder(out).Encode(struct {
+				File  string `json:"file"`
+				Title string `json:"title"`
+				*title.Position
+			}{f.Path, f.Title, f.Position})
+		case quiet && nullSep:
+			fmt.Fprintf(out, "%s\x00", f.Title)
+		case quiet:
+			fmt.Fprintf(out, "%s%s\n%s", f.Title, byAuthors(f.Authors), indented(f.Abstract))
+		case nullSep:
+			fmt.Fprintf(out, "%s\x00%s\x00", f.Path, f.Title)
+		default:
+			fmt.Fprintf(out, "%s: %s%s\n%s", f.Path, f.Title, byAuthors(f.Authors), indented(f.Abstract))
+		}
+		sum.add(failed, f.Title == "", f.Decoded)
+	}
+
+	var pending []fileResult
+	for _, fname := range files {
+		if prog != nil {
+			prog.next(fname)
+		}
 
-// phrasesOfDoc extracts the phrases of document.
-// We pass the document with a builder func to handle pdf reader
-// panics in one place.
-func phrasesOfDoc(docgen func() (*pdf.Reader, error)) (phrases []*phrase, rerr error) {
-	defer func() {
-		if val := recover(); val != nil {
-			// do not send garbage to output
-			var errStr string
-			if err, ok := val.(error); ok {
-				errStr = err.Error()
+		if perDocument {
+			docs, err := extractor.Documents(fname)
+			sum.add(err != nil, len(docs) == 0, false)
+			if err == nil {
+				if !showPosition {
+					for i := range docs {
+						docs[i].Position = nil
+					}
+				}
+				json.NewEncoder(out).Encode(struct {
+					File      string           `json:"file"`
+					Documents []title.Document `json:"documents"`
+				}{fname, docs})
 			} else {
-				errStr = fmt.Sprint(val)
+				fmt.Fprintf(os.Stderr, "error: %s: %v\n", fname, err)
 			}
-			if i := strings.Index(errStr, "malformed hex string"); i >= 0 {
-				rerr = errors.New("reader paniced: malformed hex string")
+			continue
+		}
+
+		if candidatesFormat != "" {
+			cands, err := extractor.Candidates(fname)
+			if errors.Is(err, title.ErrNoText) {
+				err = nil
+			}
+			sum.add(err != nil, false, false)
+			if err == nil {
+				json.NewEncoder(out).Encode(struct {
+					File       string            `json:"file"`
+					Candidates []title.Candidate `json:"candidates"`
+				}{fname, cands})
 			} else {
-				rerr = fmt.Errorf("reader paniced: %s", errStr)
+				fmt.Fprintf(os.Stderr, "error: %s: %v\n", fname, err)
 			}
+			continue
 		}
-	}()
 
-	doc, err := docgen()
-	if err != nil {
-		return nil, fmt.Errorf("can't init reader: %w", err)
-	}
+		if topN > 0 {
+			cands, err := extractor.Ranked(fname)
+			failed := err != nil && !errors.Is(err, title.ErrNoText)
+			if failed {
+				fmt.Fprintf(os.Stderr, "error: %s: %v\n", fname, err)
+			}
+			sum.add(failed, len(cands) == 0, false)
+			for _, c := range cands[:min(topN, len(cands))] {
+				fmt.Fprintf(out, "%s: %.3f: %s\n", fname, c.Score, c.Text)
+			}
+			continue
+		}
 
-	var firstPage pdf.Page
-	for i := 1; i <= doc.NumPage(); i++ {
-		if p := doc.Page(i); !p.V.IsNull() {
-			firstPage = p
-			break
+		if tuneSpacing {
+			trials, err := extractor.Tune(fname)
+			failed := err != nil && !errors.Is(err, title.ErrNoText)
+			if failed {
+				fmt.Fprintf(os.Stderr, "error: %s: %v\n", fname, err)
+			}
+			sum.add(failed, false, false)
+			for _, t := range trials {
+				fmt.Fprintf(out, "%s: %.2f: %s\n", fname, t.Spacing, t.Title)
+			}
+			continue
 		}
-	}
-	if firstPage.V.IsNull() {
-		return nil, nil
-	}
 
-	var currPhrase *phrase
-	for _, t := range firstPage.Content().Text {
-		if currPhrase == nil {
-			currPhrase = newPhrase(t)
-		} else if !currPhrase.tryAppend(t) {
-			phrases = append(phrases, currPhrase)
-			currPhrase = newPhrase(t)
+		f := extractFile(extractor, fname)
+		if f.Error == "" && interactive {
+			cands, _ := extractor.Ranked(fname)
+			tl, err := chooseTitle(stdin, os.Stderr, fname, f.Title, cands)
+			if err != nil {
+				f.Error = err.Error()
+			}
+			f.Title = tl
+		}
+		if sortBy != "" || findDups {
+			pending = append(pending, f)
+		} else {
+			emit(f)
 		}
 	}
-	if currPhrase != nil {
-		phrases = append(phrases, currPhrase)
+	slices.SortStableFunc(pending, func(a, b fileResult) int {
+		switch sortBy {
+		case "title":
+			return cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		case "confidence":
+			return cmp.Compare(b.Confidence, a.Confidence)
+		}
+		return cmp.Compare(a.Path, b.Path)
+	})
+	if findDups {
+		for _, f := range pending {
+			sum.add(f.Error != "", f.Title == "", f.Decoded)
+			if f.Error != "" {
+				fmt.Fprintf(os.Stderr, "error: %s: %s\n", f.Path, f.Error)
+			}
+		}
+		printDuplicates(out, duplicates(pending))
+		pending = nil
 	}
-
-	if len(phrases) == 0 {
-		return nil, nil
+	for _, f := range pending {
+		emit(f)
 	}
-	return
-}
-
-// titleFromPhrases tries to guess which of the phrases is the document title.
-func titleFromPhrases(phrases []*phrase) string {
-	// sort by decreasing font size. We expect the title to be the phrase
-	// with the largest font size unless it is very short.
-	// The most common case is a text paragraph after the title
-	// that starts with a very big letter.
-	slices.SortFunc(phrases, func(a, b *phrase) int {
-		return cmp.Compare(b.fontSize, a.fontSize)
-	})
 
-	if len(phrases) == 0 {
-		return ""
+	if prog != nil {
+		prog.finish()
 	}
-	tl := phrases[0].String()
-	if len(tl) < 4 {
-		if len(phrases) > 1 {
-			tl = phrases[1].String()
-		} else {
-			tl = ""
+	if table != nil {
+		table.Flush()
+	}
+	if jsonOutput {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		enc.Encode(records)
+	}
+
+	if outFile != nil {
+		if err := outFile.commit(); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
 		}
 	}
+	if showSummary {
+		sum.write(os.Stderr)
+	}
 
-	if disableWordsCheck || dictCheck(tl) {
-		return tl
+	switch {
+	case sum.failed > 0:
+		os.Exit(1)
+	case sum.untitled > 0:
+		os.Exit(3)
 	}
-	return ""
 }
 
-// phrase represents a list of words that probably form a single phrase.
-// Phrases are defined loosely by checking letter font properties.
-type phrase struct {
-	font     string
-	fontSize float64
-	spacing  float64
-	prevx    float64
-	prevy    float64
-	length   int
-	b        strings.Builder
+// applyConfig sets the flags of set with the values of the configuration file.
+func applyConfig(set *flag.FlagSet) error {
+	if configFile != "" {
+		return loadConfig(set, configFile, false)
+	}
+	if fname := defaultConfigFile(); fname != "" {
+		return loadConfig(set, fname, true)
+	}
+	return nil
 }
 
-// newPhrases returns a new phrase starting with t.
-func newPhrase(t pdf.Text) *phrase {
-	p := &phrase{
-		font:     t.Font,
-		fontSize: t.FontSize,
-		spacing:  spacingCoefficient * t.FontSize,
-	}
-	p.b.WriteString(printable(t.S))
-	p.length += len(t.S)
-	p.prevx = t.X + t.W
-	p.prevy = t.Y
-	return p
+// record is the result of a file for the structured output formats.
+type record struct {
+	Path       string   `json:"path"`
+	Title      string   `json:"title"`
+	Subtitle   string   `json:"subtitle,omitempty"`
+	Authors    []string `json:"authors,omitempty"`
+	Abstract   string   `json:"abstract,omitempty"`
+	DOI        string   `json:"doi,omitempty"`
+	ArXiv      string   `json:"arxiv,omitempty"`
+	ISBNs      []string `json:"isbns,omitempty"`
+	Year       int      `json:"year,omitempty"`
+	Venue      string   `json:"venue,omitempty"`
+	Keywords   []string `json:"keywords,omitempty"`
+	Profile    string   `json:"profile,omitempty"`
+	Author     string   `json:"author,omitempty"`
+	Confidence float64  `json:"confidence"`
+	Error      string   `json:"error,omitempty"`
+	ErrorClass string   `json:"error_class,omitempty"`
+	ElapsedMS  float64  `json:"elapsed_ms"`
+	Pages      int      `json:"pages"`
+	TextRuns   int      `json:"text_runs"`
+	Phrases    int      `json:"phrases"`
+	Decoded    bool     `json:"decoded"`
+
+	// Position is the position of the title with -position.
+	*title.Position
 }
 
-// tryAppend tries to add t to the phrase and returns true if successful.
-func (p *phrase) tryAppend(t pdf.Text) bool {
-	// after some tests, it seems that if we are a bit loose with
-	// font names and sizes we can do better. Presentation slides
-	// use many fonts and both upper and lower case letters.
-	// Technical articles use standard fonts so names do not matter
-	fontFits := true
-	fontSizeFits := math.Abs(t.FontSize-p.fontSize) < 4.0
-	canAppend := fontSizeFits && fontFits
-	if !canAppend {
-		return false
-	}
-
-	// do not add the separator at the beginning
-	if p.length > 0 {
-		if t.Y < p.prevy || t.X-p.prevx >= p.spacing {
-			p.b.WriteString(" ")
-			p.length++
-		}
+// status returns "ok" if r has a title, "notitle" if it does not
+// and "error" if the extraction failed.
+func (r record) status() string {
+	switch {
+	case r.Error != "":
+		return "error"
+	case r.Title == "":
+		return "notitle"
 	}
-	p.b.WriteString(printable(t.S))
-	p.length += len(t.S)
-	p.prevx = t.X + t.W
-	p.prevy = t.Y
-	return true
+	return "ok"
 }
 
-// String returns the phrase as a single string.
-func (p *phrase) String() string {
-	// trim for the cases it misses the title and
-	// returns the document full text
-	s := strings.Join(strings.Fields(p.b.String()), " ")
-	return s[0:min(80, len(s))]
+// byAuthors returns the authors as a suffix of the title,
+// like " — Jane Doe, John Smith", or the empty string if there are none.
+func byAuthors(authors []string) string {
+	if len(authors) == 0 {
+		return ""
+	}
+	return " — " + strings.Join(authors, ", ")
 }
 
-// dictCheck returns true if s contains enough dictionary words.
-func dictCheck(s string) bool {
-	tlwords := 0
-	tlwordsInDict := 0
-	for _, w := range wordsExtractor.FindAllString(s, -1) {
-		// stemmer is very aggressive, for example it outputs
-		// decline->declin, computers->comput.
-		// Best to check both original word and stemmed.
-		if words[strings.ToLower(w)] || words[strings.ToLower(stemmer.Stem(w))] {
-			tlwordsInDict++
-		}
-		tlwords++
+// indented returns the line s indented by a tab, or the empty string if s is empty.
+func indented(s string) string {
+	if s == "" {
+		return ""
 	}
-	return tlwords > 0 &&
-		float64(tlwordsInDict)/float64(tlwords) >= wordsInDictPercent
+	return "\t" + s + "\n"
 }
 
-// printable returns a copy of s where all non printable characters
-// are replaced by a space.
-func printable(s string) string {
-	const space = rune(32)
-
-	runes := make([]rune, 0)
-	for {
-		r, siz := utf8.DecodeRuneInString(s)
-		if siz == 0 {
-			break
-		}
-		if r == utf8.RuneError {
-			runes = append(runes, space)
-		} else if unicode.IsGraphic(r) {
-			runes = append(runes, r)
-		} else {
-			runes = append(runes, space)
-		}
-		s = s[siz:]
+// record returns f as a record.
+func (f fileResult) record() record {
+	r := record{
+		Path:       f.Path,
+		Title:      f.Title,
+		Subtitle:   f.Subtitle,
+		Authors:    f.Authors,
+		Abstract:   f.Abstract,
+		DOI:        f.DOI,
+		ArXiv:      f.ArXiv,
+		ISBNs:      f.ISBNs,
+		Year:       f.Year,
+		Venue:      f.Venue,
+		Keywords:   f.Keywords,
+		Profile:    f.Profile,
+		Author:     f.Author,
+		Confidence: f.Confidence,
+		Error:      f.Error,
+		ErrorClass: f.ErrorClass,
+		ElapsedMS:  float64(f.Elapsed.Microseconds()) / 1000,
+		Pages:      f.Pages,
+		TextRuns:   f.TextRuns,
+		Phrases:    f.Phrases,
+		Decoded:    f.Decoded,
 	}
-	return string(runes)
+	if showPosition {
+		r.Position = f.Position
+	}
+	return r
 }
 
-// decodedWithGhostscript runs ghostscript to produce a deflated, uncompressed pdf.
-func decodedWithGhostscript(fname string) (*bytes.Buffer, error) {
-	fout := bytes.NewBuffer(make([]byte, 0, 10*1024*1024))
+// fileResult is the result of a file. It is the data of the -f template.
+type fileResult struct {
+	Path       string
+	Error      string
+	ErrorClass string
+	title.Result
+}
 
-	args := []string{
-		"-dNOPAUSE",
-		"-dBATCH",
-		"-dSAFER",
-		"-dQUIET",
-		"-sDEVICE=pdfwrite",
-		"-sOutputFile=-",
-		"-dFirstPage=1",
-		"-dLastPage=1",
-		fname,
+// extractFile extracts the title of fname.
+func extractFile(extractor *title.Extractor, fname string) fileResult {
+	res, err := extractor.ExtractResult(context.Background(), fname)
+	f := fileResult{Path: fname, Result: res}
+	// pages without text have no title, not an error
+	if err != nil && !errors.Is(err, title.ErrNoText) {
+		f.Error = err.Error()
+		f.ErrorClass = errorClass(err)
 	}
+	return f
+}
 
-	ctx, cancelFunc := context.WithTimeout(context.Background(), 1*time.Minute)
-	defer cancelFunc()
-
-	cmd := exec.CommandContext(ctx, gsCmd, args...)
-	cmd.Stdout = fout
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to transform %q: %w", fname, err)
-	}
-	if err := cmd.Wait(); err != nil {
-		return nil, fmt.Errorf("failed to transform %q: %w", fname, err)
+// errorClass returns the class of err for programs that
+// handle failures: not_found, permission, io, encrypted,
+// malformed, garbled, timeout, canceled or other.
+func errorClass(err error) string {
+	var pathErr *fs.PathError
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return "not_found"
+	case errors.Is(err, fs.ErrPermission):
+		return "permission"
+	case errors.Is(err, title.ErrEncrypted):
+		return "encrypted"
+	case errors.Is(err, title.ErrMalformed):
+		return "malformed"
+	case errors.Is(err, title.ErrGarbled):
+		return "garbled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &pathErr):
+		return "io"
 	}
-	return fout, nil
+	return "other"
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+)
+
+// atomicFile is a file written to a temporary file in the same
+// directory and renamed to its name when committed, so that readers
+// never see a partial file.
+type atomicFile struct {
+	*bufio.Writer
+	name string
+	tmp  *os.File
+}
+
+// createAtomic creates an atomicFile for the file name.
+func createAtomic(name string) (*atomicFile, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		return nil, err
+	}
+	return &atomicFile{Writer: bufio.NewWriter(tmp), name: name, tmp: tmp}, nil
+}
+
+// commit writes the buffered data and renames the temporary file
+// to the name of f. If it fails, the temporary file is removed.
+func (f *atomicFile) commit() error {
+	err := f.Flush()
+	if cerr := f.tmp.Chmod(0o644); err == nil {
+		err = cerr
+	}
+	if serr := f.tmp.Sync(); err == nil {
+		err = serr
+	}
+	if cerr := f.tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.tmp.Name(), f.name)
+	}
+	if err != nil {
+		os.Remove(f.tmp.Name())
+	}
+	return err
+}
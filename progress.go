@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// progress reports the progress of the extraction of a batch of files.
+type progress struct {
+	w     io.Writer
+	total int
+	done  int
+	start time.Time
+}
+
+// newProgress returns a progress for total files that writes to w.
+func newProgress(w io.Writer, total int) *progress {
+	return &progress{w: w, total: total, start: time.Now()}
+}
+
+// next reports that the extraction of fname starts.
+// The report overwrites the previous one on the same line.
+func (p *progress) next(fname string) {
+	eta := "?"
+	if p.done > 0 {
+		perFile := time.Since(p.start) / time.Duration(p.done)
+		eta = (perFile * time.Duration(p.total-p.done)).Round(time.Second).String()
+	}
+	fmt.Fprintf(p.w, "\r\x1b[K%d/%d eta %s %s", p.done, p.total, eta, fname)
+	p.done++
+}
+
+// finish reports that all files are done.
+func (p *progress) finish() {
+	fmt.Fprintf(p.w, "\r\x1b[K%d/%d done in %s\n", p.done, p.total, time.Since(p.start).Round(time.Millisecond))
+}
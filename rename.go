@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/anastasop/pdftitle/title"
+)
+
+// runRename is the rename command. It renames each pdf file
+// to its title.
+func runRename(args []string) {
+	set := flag.NewFlagSet("rename", flag.ExitOnError)
+	addExtractorFlags(set)
+	dryRun := set.Bool("n", false, "print the renames without renaming")
+	force := set.Bool("force", false, "replace existing files")
+	ask := set.Bool("i", false, "show the top candidate titles of each file on stderr and read the choice, or a title, from stdin")
+	set.Usage = commandUsage(set, "rename [flags] file..", `Rename renames each pdf file to its title, keeping the directory
+and the extension. Files without a title are not renamed.
+It prints a line "old -> new" for each rename.`)
+	parseFlags(set, args)
+	if set.NArg() == 0 {
+		set.Usage()
+	}
+
+	extractor := newExtractor()
+	stdin := bufio.NewReader(os.Stdin)
+	failed := false
+	for _, fname := range expandGlobs(set.Args()) {
+		tl, err := extractor.Extract(fname)
+		if errors.Is(err, title.ErrNoText) {
+			err = nil
+		}
+		if err == nil && *ask {
+			cands, _ := extractor.Ranked(fname)
+			tl, err = chooseTitle(stdin, os.Stderr, fname, tl, cands)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s: %v\n", fname, err)
+			failed = true
+			continue
+		}
+		name := fileName(tl)
+		if name == "" {
+			fmt.Fprintf(os.Stderr, "%s: no title\n", fname)
+			continue
+		}
+
+		newName := filepath.Join(filepath.Dir(fname), name+filepath.Ext(fname))
+		if newName == fname {
+			continue
+		}
+		if _, err := os.Stat(newName); err == nil && !*force {
+			fmt.Fprintf(os.Stderr, "error: %s: %s exists\n", fname, newName)
+			failed = true
+			continue
+		} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "error: %s: %v\n", fname, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("%s -> %s\n", fname, newName)
+		if !*dryRun {
+			if err := os.Rename(fname, newName); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+				failed = true
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// fileName returns tl as a file name that is valid on all common
+// filesystems. Path separators, characters reserved on windows and
+// control characters become spaces and spaces are collapsed.
+func fileName(tl string) string {
+	s := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, tl)
+	return strings.Trim(strings.Join(strings.Fields(s), " "), ". ")
+}
+
+// commandUsage returns a usage func for the command of set
+// with the synopsis and the description.
+func commandUsage(set *flag.FlagSet, synopsis, description string) func() {
+	return func() {
+		fmt.Fprintf(os.Stderr, "usage: pdftitle %s\n\n%s\n\nFlags:\n", synopsis, description)
+		set.PrintDefaults()
+		os.Exit(2)
+	}
+}
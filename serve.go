@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/anastasop/pdftitle/title"
+)
+
+// runServe is the serve command. It serves the titles
+// of pdf files posted over http.
+func runServe(args []string) {
+	set := flag.NewFlagSet("serve", flag.ExitOnError)
+	addExtractorFlags(set)
+	addr := set.String("addr", "localhost:8080", "listen on `address`")
+	maxSize := set.Int64("max-size", 100<<20, "maximum size of a pdf in bytes")
+	set.Usage = commandUsage(set, "serve [flags]", `Serve listens for http requests with a pdf file as body, like
+curl --data-binary @paper.pdf http://localhost:8080/, and replies
+with a json object with the title, or the error, of the file.`)
+	parseFlags(set, args)
+
+	extractor := newExtractor()
+	http.HandleFunc("POST /", func(w http.ResponseWriter, r *http.Request) {
+		var reply struct {
+			Title string `json:"title"`
+			Error string `json:"error,omitempty"`
+		}
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, *maxSize))
+		status := http.StatusOK
+		if err != nil {
+			status = http.StatusBadRequest
+		} else {
+			reply.Title, err = extractor.ExtractReader(bytes.NewReader(data), int64(len(data)))
+			if errors.Is(err, title.ErrNoText) {
+				err = nil
+			}
+			if err != nil {
+				status = http.StatusUnprocessableEntity
+			}
+		}
+		if err != nil {
+			reply.Error = err.Error()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(reply)
+	})
+
+	fmt.Fprintf(os.Stderr, "listening on %s\n", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
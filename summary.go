@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// summary counts the outcomes of the files of a run.
+type summary struct {
+	start     time.Time
+	processed int
+	failed    int
+	untitled  int
+	decoded   int
+}
+
+// newSummary returns a summary of a run that starts now.
+func newSummary() *summary {
+	return &summary{start: time.Now()}
+}
+
+// add counts a file. A failed file is not counted as untitled.
+func (s *summary) add(failed, untitled, decoded bool) {
+	s.processed++
+	if failed {
+		s.failed++
+	} else if untitled {
+		s.untitled++
+	}
+	if decoded {
+		s.decoded++
+	}
+}
+
+// write prints s to w.
+func (s *summary) write(w io.Writer) {
+	fmt.Fprintf(w, "processed: %d\n", s.processed)
+	fmt.Fprintf(w, "succeeded: %d\n", s.processed-s.failed)
+	fmt.Fprintf(w, "failed: %d\n", s.failed)
+	fmt.Fprintf(w, "empty titles: %d\n", s.untitled)
+	fmt.Fprintf(w, "ghostscript fallbacks: %d\n", s.decoded)
+	fmt.Fprintf(w, "time: %s\n", time.Since(s.start).Round(time.Millisecond))
+}
@@ -0,0 +1,72 @@
+package title
+
+import (
+	"regexp"
+	"strings"
+
+	"rsc.io/pdf"
+)
+
+// abstractPages is the number of pages, from the title page,
+// searched for the abstract.
+const abstractPages = 2
+
+// abstractHeading matches the heading of the abstract and
+// the punctuation that follows it.
+var abstractHeading = regexp.MustCompile(`\b(?:Abstract|ABSTRACT)\b[\s.:—–-]*`)
+
+// abstractEnd matches the headings that follow the abstract.
+var abstractEnd = regexp.MustCompile(`\b(?:Keywords|KEYWORDS|Key words|Index Terms|CCS Concepts|ACM Reference Format|(?:1\.?\s+|I\.\s+)?(?:Introduction|INTRODUCTION))\b`)
+
+// textOfPages returns the texts of the phrases of n pages of doc,
+// starting from page first, in reading order. Unlike the title
+// candidates, they include the phrases in margins and rotated text.
+func (e *Extractor) textOfPages(doc *pdf.Reader, first, n int) []string {
+	var texts []string
+	for i := max(first, 1); i < first+n && i <= e.numPage(doc); i++ {
+		page := doc.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		for _, p := range e.phrasesOfPage(page) {
+			texts = append(texts, p.text())
+		}
+	}
+	return texts
+}
+
+// abstractOf returns the paragraph after the Abstract heading in the
+// first pages of doc, from the title page, or the empty string if
+// there is none. The paragraph is the rest of the phrase of the
+// heading, or the next phrase if the heading is a phrase by itself,
+// up to the heading that follows the abstract, like Keywords.
+func (e *Extractor) abstractOf(doc *pdf.Reader) (string, error) {
+	_, num, err := e.titlePage(doc)
+	if isNoText(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return afterHeading(e.textOfPages(doc, num, abstractPages), abstractHeading), nil
+}
+
+// afterHeading returns the text after the first heading in texts,
+// the rest of the text of the heading or the next text if the heading
+// is a text by itself, up to the heading that follows the abstract.
+func afterHeading(texts []string, heading *regexp.Regexp) string {
+	for i, s := range texts {
+		loc := heading.FindStringIndex(s)
+		if loc == nil {
+			continue
+		}
+		rest := strings.TrimSpace(s[loc[1]:])
+		if rest == "" && i+1 < len(texts) {
+			rest = texts[i+1]
+		}
+		if end := abstractEnd.FindStringIndex(rest); end != nil {
+			rest = rest[:end[0]]
+		}
+		return strings.TrimSpace(rest)
+	}
+	return ""
+}
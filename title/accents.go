@@ -0,0 +1,147 @@
+package title
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// spacingAccents are the combining marks of the spacing accents that
+// fonts of TeX and old producers draw as letters next to the accented
+// letters, like the ¨ of Schro¨dinger.
+var spacingAccents = map[rune]rune{
+	'¨': '\u0308', // diaeresis
+	'´': '\u0301', // acute
+	'ˆ': '\u0302', // circumflex
+	'˜': '\u0303', // tilde
+	'¯': '\u0304', // macron
+	'˘': '\u0306', // breve
+	'˙': '\u0307', // dot above
+	'˚': '\u030A', // ring above
+	'˝': '\u030B', // double acute
+	'ˇ': '\u030C', // caron
+	'¸': '\u0327', // cedilla
+	'˛': '\u0328', // ogonek
+}
+
+// vowels are the letters the spacing acute accent is attached to.
+// Next to other letters it is likely an apostrophe, like in don´t.
+const vowels = "aeiouyAEIOUY"
+
+// normalizeAccents returns s in Unicode NFC with the accents attached to
+// their letters. Spacing accents and combining marks separated from their
+// letters by spaces are attached to the letter before them, or after them,
+// if they compose to an accented letter, and combining marks without a
+// letter are dropped.
+func normalizeAccents(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	runes := []rune(s)
+	out := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		mark, spacing := spacingAccents[r]
+		switch {
+		case spacing:
+		case unicode.Is(unicode.Mn, r):
+			mark = r
+		default:
+			out = append(out, r)
+			continue
+		}
+
+		// the letter before, skipping the spaces a combining mark
+		// drawn apart from its letter is written after.
+		j := len(out) - 1
+		for !spacing && j >= 0 && out[j] == ' ' {
+			j--
+		}
+		next := i+1 < len(runes) && composes(runes[i+1], mark, r == '´')
+		if next && spacing && j >= 0 && prefersNext(out[j], runes[i+1], mark) {
+			runes[i+1] = composed(runes[i+1], mark)
+			continue
+		}
+		if j >= 0 && composes(out[j], mark, r == '´') {
+			out = append(out[:j], composed(out[j], mark))
+			continue
+		}
+		if !spacing && j == len(out)-1 && j >= 0 && (unicode.IsLetter(out[j]) || unicode.Is(unicode.Mn, out[j])) {
+			// a combining mark of a letter without a precomposed
+			// form, or one of the marks of a letter.
+			out = append(out, mark)
+			continue
+		}
+		if next {
+			runes[i+1] = composed(runes[i+1], mark)
+			continue
+		}
+		if spacing {
+			out = append(out, r)
+		}
+	}
+	return norm.NFC.String(string(out))
+}
+
+// composes returns true if the letter r and the combining mark compose
+// to a precomposed letter. If onlyVowels is set, r must be a vowel.
+func composes(r, mark rune, onlyVowels bool) bool {
+	if !unicode.IsLetter(r) || onlyVowels && !strings.ContainsRune(vowels, r) {
+		return false
+	}
+	return utf8.RuneCountInString(composedString(r, mark)) == 1
+}
+
+// consonantMarks are the letters of the combining marks of accents
+// mostly of consonants, like the caron of č or the cedilla of ç.
+var consonantMarks = map[rune]string{
+	'\u030C': "cCdDnNrRsStTzZ",
+	'\u0327': "cCsStT",
+}
+
+// prefersNext returns true if the spacing accent mark between the
+// letters prev and next is rather the accent of next. Accents are
+// attached to vowels, except the tilde of ñ and the carons
+// and cedillas of consonants.
+func prefersNext(prev, next, mark rune) bool {
+	switch {
+	case mark == '\u0303' && (next == 'n' || next == 'N'):
+		return true
+	case consonantMarks[mark] != "":
+		return !strings.ContainsRune(consonantMarks[mark], prev)
+	}
+	return !composes(prev, mark, false) || !strings.ContainsRune(vowels, prev) && strings.ContainsRune(vowels, next)
+}
+
+// composed returns the precomposed letter of r and mark.
+func composed(r, mark rune) rune {
+	c, _ := utf8.DecodeRuneInString(composedString(r, mark))
+	return c
+}
+
+// composedString returns r and mark in NFC.
+func composedString(r, mark rune) string {
+	return norm.NFC.String(string([]rune{r, mark}))
+}
+
+// isASCII returns true if s has only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize normalizes the accents of the text of p.
+func (p *phrase) normalize() {
+	s := p.b.String()
+	if isASCII(s) {
+		return
+	}
+	p.b.Reset()
+	p.b.WriteString(normalizeAccents(s))
+}
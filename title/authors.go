@@ -0,0 +1,110 @@
+package title
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxAuthorPhrases is the maximum number of phrases
+// below the title searched for author names.
+const maxAuthorPhrases = 3
+
+// authorSeparators split author lines into names.
+var authorSeparators = regexp.MustCompile(`\s*(?:,|;|&|\band\b|\n)\s*`)
+
+// nameToken matches the words of names: capitalized words, with
+// hyphens and apostrophes, initials like J. or J.-P. and words in
+// capitals. Name particles, like van or de, are matched by nameParticles.
+var nameToken = regexp.MustCompile(`^(?:\p{Lu}[\p{Ll}\p{M}'’]+(?:-\p{Lu}[\p{Ll}\p{M}'’]+)?|\p{Lu}\.(?:-?\p{Lu}\.)*|\p{Lu}{2,})$`)
+
+// nameParticles are the lower case words of names, like van in Guido van Rossum.
+var nameParticles = []string{"da", "de", "del", "der", "di", "du", "la", "le", "van", "von"}
+
+// notNames are the capitalized words, in lower case, that start the text
+// following author names, like affiliations and the abstract.
+var notNames = []string{
+	"abstract", "college", "corporation", "department", "dept", "inc", "institute",
+	"introduction", "keywords", "lab", "laboratory", "school", "the", "this",
+	"university", "we",
+}
+
+// authorMarks are the footnote marks and affiliation numbers
+// that follow author names.
+const authorMarks = "0123456789*†‡§¶#"
+
+// authorsOf returns the names of the authors in the phrases below the
+// title phrase tp of phrases, in a smaller font. The subtitle sp, if
+// not nil, is skipped. Author lines are usually the first phrases
+// below the title and the names are words shaped like names
+// separated by commas or and.
+func (e *Extractor) authorsOf(phrases []*phrase, tp, sp *phrase) []string {
+	var below []*phrase
+	for _, p := range phrases {
+		if p == tp || p == sp || p.source != "" || p.page != tp.page {
+			continue
+		}
+		if p.y < tp.prevy && p.fontSize < tp.fontSize {
+			below = append(below, p)
+		}
+	}
+	slices.SortStableFunc(below, func(a, b *phrase) int {
+		return cmp.Compare(b.y, a.y)
+	})
+
+	var authors []string
+	for _, p := range below[:min(len(below), maxAuthorPhrases)] {
+		names := authorNames(p.b.String())
+		if len(names) == 0 && len(authors) > 0 {
+			break
+		}
+		authors = append(authors, names...)
+	}
+	if len(authors) > 0 {
+		e.logger.Debug("authors", "names", authors)
+	}
+	return authors
+}
+
+// authorNames returns the names at the start of the author line s.
+// The names end at the first part of s that is not only a name.
+func authorNames(s string) []string {
+	var names []string
+	for _, part := range authorSeparators.Split(s, -1) {
+		var name []string
+		words := strings.Fields(part)
+		for _, w := range words {
+			w = strings.TrimRight(w, authorMarks)
+			if !isNameWord(w, len(name) == 0) {
+				break
+			}
+			name = append(name, w)
+		}
+		// drop trailing particles, they must be followed by a name.
+		for len(name) > 0 && slices.Contains(nameParticles, name[len(name)-1]) {
+			name = name[:len(name)-1]
+		}
+		if len(name) >= 2 && len(name) <= 4 {
+			names = append(names, strings.Join(name, " "))
+		}
+		if len(name) < len(words) || len(name) < 2 {
+			if part != "" {
+				break
+			}
+		}
+	}
+	return names
+}
+
+// isNameWord returns true if w looks like a word of a name.
+// Particles can not be the first word.
+func isNameWord(w string, first bool) bool {
+	if slices.Contains(notNames, strings.ToLower(w)) {
+		return false
+	}
+	if !first && slices.Contains(nameParticles, w) {
+		return true
+	}
+	return nameToken.MatchString(w)
+}
@@ -0,0 +1,69 @@
+package title
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"runtime"
+	"sync"
+)
+
+// Batch extracts the titles of paths with workers goroutines and
+// calls fn with the result of each path as it completes. If workers
+// is not positive, it is the number of CPUs. Calls of fn are not
+// concurrent. Batch stops reading paths when ctx is done.
+//
+// Batch returns the errors of all paths, each prefixed with its path,
+// joined with errors.Join, or nil if there were none.
+func (e *Extractor) Batch(ctx context.Context, paths iter.Seq[string], workers int, fn func(path string, res Result, err error)) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	type result struct {
+		path string
+		res  Result
+		err  error
+	}
+	jobs := make(chan string)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				res, err := e.ExtractResult(ctx, path)
+				results <- result{path, res, err}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for path := range paths {
+			select {
+			case jobs <- path:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var errs []error
+	for r := range results {
+		fn(r.path, r.res, r.err)
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.path, r.err))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,79 @@
+package title
+
+import (
+	"strings"
+	"unicode"
+)
+
+// isIdeographic returns true if r is a Chinese or Japanese letter,
+// a Han ideograph or a kana, of scripts written without spaces
+// between words.
+func isIdeographic(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// isCJK returns true if r is a Chinese, Japanese or Korean letter.
+func isCJK(r rune) bool {
+	return isIdeographic(r) || unicode.Is(unicode.Hangul, r)
+}
+
+// isCommonCJK returns true if r is a letter of the common blocks of
+// CJK text, the CJK unified ideographs, the kana and the Hangul
+// syllables. The letters of the other blocks, like the extensions of
+// the ideographs, the radicals and the compatibility ideographs, are
+// rare in titles and frequent in the text of fonts whose codes are
+// not mapped to the right letters.
+func isCommonCJK(r rune) bool {
+	switch {
+	case r >= 0x4E00 && r <= 0x9FFF:
+		return true
+	case r >= 0x3040 && r <= 0x30FF:
+		return true
+	case r >= 0xAC00 && r <= 0xD7A3:
+		return true
+	}
+	return false
+}
+
+// cjkRatio returns, for the text s mostly of CJK letters, the fraction
+// of its CJK letters of the common blocks, see isCommonCJK, in place of
+// the fraction of dictionary words, and the number of its words and true.
+// Chinese and Japanese are written without spaces so every two letters
+// count as a word, and Korean words are separated by spaces. It returns
+// false if s is not mostly of CJK letters.
+func cjkRatio(s string) (float64, int, bool) {
+	letters, cjk, common, ideographs := 0, 0, 0, 0
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if isCJK(r) {
+			cjk++
+			if isCommonCJK(r) {
+				common++
+			}
+			if isIdeographic(r) {
+				ideographs++
+			}
+		}
+	}
+	if cjk < 2 || 2*cjk < letters {
+		return 0, 0, false
+	}
+	words := (ideographs + 1) / 2
+	for _, w := range strings.Fields(s) {
+		if strings.ContainsFunc(w, func(r rune) bool { return unicode.Is(unicode.Hangul, r) }) {
+			words++
+		}
+	}
+	return float64(common) / float64(cjk), words, true
+}
+
+// joinsIdeographs returns true if the letters a and b, on the same line,
+// are ideographs, see isIdeographic, that are in the same phrase without
+// a space, unless they are more than a letter apart, since justified CJK
+// text spreads the letters of lines.
+func joinsIdeographs(a, b rune, gap, fontSize float64) bool {
+	return isIdeographic(a) && isIdeographic(b) && gap < fontSize
+}
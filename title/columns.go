@@ -0,0 +1,186 @@
+package title
+
+import (
+	"math"
+	"slices"
+
+	"rsc.io/pdf"
+)
+
+// minColumnRuns is the minimum number of text runs
+// of a page searched for columns.
+const minColumnRuns = 100
+
+// gutter returns the left and right x of the empty vertical band
+// between the two columns of a page with text in two columns,
+// and false if the text is not in two columns. The title and
+// other lines spanning both columns cross the band with
+// a few letters so the band has few letters, not none.
+func gutter(text []pdf.Text, orients []orientation) (lo, hi float64, ok bool) {
+	if len(text) < minColumnRuns {
+		return 0, 0, false
+	}
+	minx, maxx := math.Inf(1), math.Inf(-1)
+	for i, t := range text {
+		if orients[i] == upright {
+			minx, maxx = min(minx, t.X), max(maxx, t.X+t.W)
+		}
+	}
+	if maxx-minx < 100 {
+		return 0, 0, false
+	}
+
+	// the number of letters over each 2pt of the width.
+	const bucket = 2.0
+	counts := make([]int, int((maxx-minx)/bucket)+1)
+	total := 0
+	for i, t := range text {
+		if orients[i] != upright {
+			continue
+		}
+		from := int((t.X - minx) / bucket)
+		to := max(from, int((t.X+t.W-minx)/bucket))
+		for b := max(from, 0); b <= min(to, len(counts)-1); b++ {
+			counts[b]++
+		}
+		total++
+	}
+	var nonzero []int
+	for _, c := range counts {
+		if c > 0 {
+			nonzero = append(nonzero, c)
+		}
+	}
+	if len(nonzero) == 0 {
+		return 0, 0, false
+	}
+	slices.Sort(nonzero)
+	few := max(1, nonzero[len(nonzero)/2]/20)
+
+	// the widest band of buckets with few letters
+	// in the middle of the text.
+	first, last := len(counts)*3/10, len(counts)*7/10
+	bestFrom, bestLen := 0, 0
+	for b := first; b <= last; b++ {
+		if counts[b] > few {
+			continue
+		}
+		e := b
+		for e+1 < len(counts) && counts[e+1] <= few {
+			e++
+		}
+		s := b
+		for s > 0 && counts[s-1] <= few {
+			s--
+		}
+		if e-s+1 > bestLen {
+			bestFrom, bestLen = s, e-s+1
+		}
+		b = e
+	}
+	if float64(bestLen)*bucket < 6 {
+		return 0, 0, false
+	}
+	lo = minx + float64(bestFrom)*bucket
+	hi = lo + float64(bestLen)*bucket
+
+	// both columns must have a good part of the text.
+	left, right := 0, 0
+	for i, t := range text {
+		switch {
+		case orients[i] != upright:
+		case t.X+t.W <= lo:
+			left++
+		case t.X >= hi:
+			right++
+		}
+	}
+	if left < total/5 || right < total/5 {
+		return 0, 0, false
+	}
+	return lo, hi, true
+}
+
+// Columns of text runs.
+const (
+	spanning = iota
+	leftColumn
+	rightColumn
+)
+
+// columnOrder returns the indices of the text runs of a page in reading
+// order by columns and the column of each run. Pages often interleave
+// the lines of two columns and tryAppend would stitch them in the same
+// phrase. Between lines that span both columns, like the title, the lines
+// of the left column come before the lines of the right column.
+// Text not in two columns keeps its order.
+func columnOrder(text []pdf.Text, orients []orientation) (order, cols []int) {
+	order = make([]int, len(text))
+	for i := range order {
+		order[i] = i
+	}
+	cols = make([]int, len(text))
+	lo, hi, ok := gutter(text, orients)
+	if !ok {
+		return order, cols
+	}
+	mid := (lo + hi) / 2
+
+	// segments are the runs of consecutive text runs on the same
+	// line and the same side of the gutter.
+	type segment struct {
+		from, to   int
+		minx, maxx float64
+	}
+	var segs []segment
+	for i, t := range text {
+		if i > 0 {
+			prev := text[i-1]
+			gap := t.X - (prev.X + prev.W)
+			size := max(min(t.FontSize, prev.FontSize), 1)
+			sameLine := orients[i] == orients[i-1] && math.Abs(t.Y-prev.Y) < size/2 && t.X >= prev.X
+			crosses := prev.X+prev.W <= lo && t.X >= hi && gap > size
+			if sameLine && !crosses {
+				s := &segs[len(segs)-1]
+				s.to = i + 1
+				s.minx, s.maxx = min(s.minx, t.X), max(s.maxx, t.X+t.W)
+				continue
+			}
+		}
+		segs = append(segs, segment{i, i + 1, t.X, t.X + t.W})
+	}
+
+	order = order[:0]
+	var left, right []int
+	flush := func() {
+		order = append(order, left...)
+		order = append(order, right...)
+		left, right = left[:0], right[:0]
+	}
+	for _, s := range segs {
+		switch {
+		case orients[s.from] != upright:
+			flush()
+			for i := s.from; i < s.to; i++ {
+				order = append(order, i)
+			}
+		case s.maxx <= mid:
+			for i := s.from; i < s.to; i++ {
+				left = append(left, i)
+				cols[i] = leftColumn
+			}
+		case s.minx >= mid:
+			for i := s.from; i < s.to; i++ {
+				right = append(right, i)
+				cols[i] = rightColumn
+			}
+		default:
+			flush()
+			for i := s.from; i < s.to; i++ {
+				order = append(order, i)
+			}
+		}
+	}
+	flush()
+	return order, cols
+}
@@ -0,0 +1,225 @@
+package title
+
+import (
+	_ "embed"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+var (
+	// wordsList is a list of words per line.
+	// It currently uses the ones from NetBSD dict
+	//go:embed words
+	wordsList string
+
+	// wordSets are wordsList as sets, lower cased with the rules
+	// of each locale. They are built on first use.
+	wordSets   = make(map[language.Tag]map[string]bool)
+	wordSetsMu sync.Mutex
+
+	// techList is a list of technical and scientific terms per line,
+	// like kubernetes or genomics, that are not in wordsList.
+	//go:embed techwords
+	techList string
+
+	// techWords is techList as a set, built on first use.
+	techWords = sync.OnceValue(func() map[string]bool {
+		words := make(map[string]bool)
+		for _, w := range strings.Fields(techList) {
+			words[w] = true
+		}
+		return words
+	})
+
+	// wordsExtractor is used to extract words, of the letters
+	// of any script and their accents, from strings.
+	wordsExtractor = regexp.MustCompile(`[\p{L}\p{M}]{3,30}`)
+)
+
+// wordsFor returns wordsList as a set, lower cased with
+// the rules of locale.
+func wordsFor(locale language.Tag) map[string]bool {
+	wordSetsMu.Lock()
+	defer wordSetsMu.Unlock()
+
+	if words, ok := wordSets[locale]; ok {
+		return words
+	}
+	lower := cases.Lower(locale)
+	words := make(map[string]bool)
+	for w := range strings.Lines(wordsList) {
+		words[lower.String(strings.TrimRight(w, "\n"))] = true
+	}
+	wordSets[locale] = words
+	return words
+}
+
+// isWord returns true if w, or its stem, is a dictionary word.
+func (e *Extractor) isWord(w string) bool {
+	return e.inDict(w, wordsFor(e.locale), cases.Lower(e.locale))
+}
+
+// inDict returns true if w, or its stem, lower cased with lower, is one
+// of words or if w is a word of WithWords or a technical term of
+// WithTechWords.
+func (e *Extractor) inDict(w string, words map[string]bool, lower cases.Caser) bool {
+	// stemmer is very aggressive, for example it outputs
+	// decline->declin, computers->comput.
+	// Best to check both original word and stemmed.
+	if words[lower.String(w)] || words[lower.String(stemOf(w, english))] || e.words[strings.ToLower(w)] {
+		return true
+	}
+	if e.techWords && techWords()[strings.ToLower(w)] {
+		return true
+	}
+	if u := withoutAccents(w); u != w {
+		return words[lower.String(u)] || words[lower.String(stemOf(u, english))]
+	}
+	return false
+}
+
+// hyphenated matches a word hyphenated at the end of a line
+// and the rest of the word on the next line.
+var hyphenated = regexp.MustCompile(`(\p{L}+)[-‐‑]\n\s*(\p{L}+)`)
+
+// dehyphenate joins the words of p hyphenated at the end of a line,
+// like "Distri- buted", if the joined word is a dictionary word.
+// Other hyphens, like those of compound words, are kept without
+// the line break.
+func (e *Extractor) dehyphenate(p *phrase) {
+	s := p.b.String()
+	if !strings.Contains(s, "\n") {
+		return
+	}
+	s = hyphenated.ReplaceAllStringFunc(s, func(m string) string {
+		sub := hyphenated.FindStringSubmatch(m)
+		head, tail := sub[1], sub[2]
+		if r, _ := utf8.DecodeRuneInString(tail); unicode.IsLower(r) && e.isWord(head+tail) {
+			return head + tail
+		}
+		return strings.Join(strings.Fields(m), "")
+	})
+	p.b.Reset()
+	p.b.WriteString(s)
+}
+
+// dictCheck returns true if s contains enough dictionary words.
+func (e *Extractor) dictCheck(s string) bool {
+	ratio, tlwords := e.dictRatio(s)
+	ok := tlwords > 0 && ratio >= e.wordsInDictPercent
+	if !ok {
+		e.logger.Debug("rejected title", "text", s, "reason", "too few dictionary words", "ratio", ratio, "words", tlwords, "threshold", e.wordsInDictPercent)
+	}
+	return ok
+}
+
+// dictRatio returns the fraction of the words of s that are
+// dictionary words and the number of words of s. Texts with less than
+// half English words may be in another language and the fraction
+// is the largest of the English one and the ones of their possible
+// languages, see languagesOf.
+// Texts mostly of CJK letters or of the letters of other scripts
+// without a dictionary, like Cyrillic, see cjkRatio and scriptRatio,
+// are checked by their letters and words.
+func (e *Extractor) dictRatio(s string) (float64, int) {
+	if ratio, n, ok := cjkRatio(s); ok {
+		return ratio, n
+	}
+	if ratio, n, ok := scriptRatio(s); ok {
+		if code := e.scriptDictionary(s); code != "" {
+			return min(ratio, e.languageRatio(s, code)), n
+		}
+		return ratio, n
+	}
+	words := wordsFor(e.locale)
+	lower := cases.Lower(e.locale)
+
+	tlwords := 0
+	tlwordsInDict := 0
+	likely := 0
+	for _, w := range wordsExtractor.FindAllString(s, -1) {
+		if e.inDict(w, words, lower) || e.acronyms[w] {
+			tlwordsInDict++
+		} else if e.likelyAcronyms && isAcronym(w) {
+			likely++
+		}
+		tlwords++
+	}
+	if tlwords == 0 {
+		return 0, 0
+	}
+	// likely acronyms count only next to dictionary
+	// words, so that all caps noise is not a title.
+	if tlwordsInDict > 0 {
+		tlwordsInDict += likely
+	}
+	ratio := float64(tlwordsInDict) / float64(tlwords)
+	if ratio < minEnglishRatio {
+		for _, code := range e.languagesOf(s) {
+			ratio = max(ratio, e.languageRatio(s, code))
+		}
+	}
+	return ratio, tlwords
+}
+
+// languageRatio returns the fraction of the words of s that are words
+// of the dictionary of the language code, see inLanguage, or acronyms.
+// Likely acronyms count only next to dictionary words, like in dictRatio.
+func (e *Extractor) languageRatio(s, code string) float64 {
+	if code == english {
+		return 0
+	}
+	words := wordsExtractor.FindAllString(s, -1)
+	n, likely := 0, 0
+	for _, w := range words {
+		if e.inLanguage(w, code) || e.words[strings.ToLower(w)] || e.acronyms[w] {
+			n++
+		} else if e.likelyAcronyms && isAcronym(w) {
+			likely++
+		}
+	}
+	if len(words) == 0 {
+		return 0
+	}
+	if n > 0 {
+		n += likely
+	}
+	return float64(n) / float64(len(words))
+}
+
+// capsRatio returns the fraction of the letters of s that are upper case.
+func capsRatio(s string) float64 {
+	letters, caps := 0, 0
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				caps++
+			}
+		}
+	}
+	if letters == 0 {
+		return 0
+	}
+	return float64(caps) / float64(letters)
+}
+
+// isAcronym returns true if w looks like an acronym,
+// an all caps word of 2 to 6 letters.
+func isAcronym(w string) bool {
+	if n := utf8.RuneCountInString(w); n < 2 || n > 6 {
+		return false
+	}
+	for _, r := range w {
+		if !unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+package title
+
+import "testing"
+
+func TestDictCheckAcronyms(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		s    string
+		want bool
+	}{
+		{"no acronyms", nil, "BERT for NLP Tasks", false},
+		{"acronyms", []Option{WithAcronyms("BERT", "NLP")}, "BERT for NLP Tasks", true},
+		{"acronyms are case sensitive", []Option{WithAcronyms("BERT", "NLP")}, "Bert for Nlp Tasks", false},
+		{"likely acronyms", []Option{WithLikelyAcronyms(true)}, "BERT for NLP Tasks", true},
+		{"likely acronyms of 2 to 6 letters", []Option{WithLikelyAcronyms(true)}, "BERTOLINO for NLP Tasks", false},
+		{"noise", []Option{WithAcronyms("BERT", "NLP")}, "QXZV KWPT JJRM", false},
+		{"noise with likely acronyms", []Option{WithLikelyAcronyms(true)}, "QXZV KWPT JJRM", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// all the words, so that a single word that is
+			// not in the dictionary rejects the title.
+			e := New(append(tt.opts, WithDictThreshold(1))...)
+			if got := e.dictCheck(tt.s); got != tt.want {
+				ratio, n := e.dictRatio(tt.s)
+				t.Errorf("dictCheck(%q) = %v, want %v (ratio %.2f of %d words)", tt.s, got, tt.want, ratio, n)
+			}
+		})
+	}
+}
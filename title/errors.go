@@ -0,0 +1,64 @@
+package title
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"rsc.io/pdf"
+)
+
+var (
+	// ErrEncrypted is returned for encrypted pdfs that cannot be
+	// read without a password or use an unsupported encryption.
+	ErrEncrypted = errors.New("encrypted pdf")
+
+	// ErrMalformed is returned for pdfs the reader cannot parse.
+	// It is also returned when the reader panics on bad input.
+	ErrMalformed = errors.New("malformed pdf")
+
+	// ErrNoText is returned when the title page has no text.
+	ErrNoText = errors.New("no text")
+
+	// ErrScannedImageOnly is returned when the title page has
+	// no text but only images, like scanned documents.
+	// It wraps ErrNoText.
+	ErrScannedImageOnly = fmt.Errorf("%w: page has only images", ErrNoText)
+
+	// ErrGarbled is returned when the title is garbled text, like
+	// 7KH 4XLFN %URZQ, usually of fonts with broken ToUnicode maps.
+	// The title is unreliable and it is not returned.
+	ErrGarbled = errors.New("garbled text")
+)
+
+// readerError classifies an error of the pdf reader init
+// with the sentinel errors.
+func readerError(err error) error {
+	var pathErr *fs.PathError
+	switch {
+	case errors.As(err, &pathErr):
+		return fmt.Errorf("can't init reader: %w", err)
+	case errors.Is(err, pdf.ErrInvalidPassword) || strings.Contains(err.Error(), "encryption"):
+		return fmt.Errorf("can't init reader: %w: %w", ErrEncrypted, err)
+	default:
+		return fmt.Errorf("can't init reader: %w: %w", ErrMalformed, err)
+	}
+}
+
+// noTextError returns ErrScannedImageOnly if page has images
+// or else ErrNoText.
+func noTextError(page pdf.Page) error {
+	xobjs := page.Resources().Key("XObject")
+	for _, k := range xobjs.Keys() {
+		if xobjs.Key(k).Key("Subtype").Name() == "Image" {
+			return ErrScannedImageOnly
+		}
+	}
+	return ErrNoText
+}
+
+// isNoText returns true if err means the title page has no text.
+func isNoText(err error) bool {
+	return errors.Is(err, ErrNoText)
+}
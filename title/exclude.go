@@ -0,0 +1,176 @@
+package title
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// pageNumber matches page numbers like 12, - 12 -, iv, Page 3 or 3 of 10.
+// Roman numerals, the pages of front matter, are well formed, below 400
+// and all lower or all upper case, so that words of their letters, like
+// Civil, MIMIC or DIV, are not page numbers. The number is the second
+// group, empty for text without one, see isPageNumber.
+var pageNumber = regexp.MustCompile(`^[-–—\[(]?\s*((?i:page)\s*)?(\d+|` +
+	`c{0,3}(?:xc|xl|l?x{0,3})(?:ix|iv|v?i{0,3})|` +
+	`C{0,3}(?:XC|XL|L?X{0,3})(?:IX|IV|V?I{0,3}))` +
+	`(\s*(/|(?i:of))\s*\d+)?\s*[-–—\])]?$`)
+
+// isPageNumber returns true if s is a page number, see pageNumber.
+func isPageNumber(s string) bool {
+	m := pageNumber.FindStringSubmatch(s)
+	return m != nil && m[2] != ""
+}
+
+// excluded returns why p is not a title candidate, because it is a page
+// number, a watermark, rotated against most text of its page, a journal
+// or conference name, a URL, an email address or a date or it is in the
+// top or bottom margin of its page like running headers and footers, or
+// the empty string if p is a candidate.
+func (e *Extractor) excluded(p *phrase) string {
+	if isPageNumber(p.String()) {
+		return "page number"
+	}
+	if p.rotated {
+		return "rotated text, like side stamps"
+	}
+	if p.diagonal() {
+		return "diagonal text, like watermarks"
+	}
+	if isStamp(p.String()) {
+		return "stamp text, like DRAFT"
+	}
+	if e.blacklisted(p.String()) {
+		return "blacklisted"
+	}
+	if isVenue(p.String()) {
+		return "venue, like Proceedings of"
+	}
+	if shape := e.shapeOf(p.String()); shape != "" {
+		return shape
+	}
+	height := p.box.Max.Y - p.box.Min.Y
+	if e.margin <= 0 || height <= 0 {
+		return ""
+	}
+	bbox := p.bbox()
+	switch {
+	case bbox[1] >= p.box.Max.Y-e.margin*height:
+		return "in the top margin"
+	case bbox[3] <= p.box.Min.Y+e.margin*height:
+		return "in the bottom margin"
+	}
+	return ""
+}
+
+// stampTexts are the texts, normalized, of stamps and watermarks.
+var stampTexts = []string{
+	"accepted manuscript", "author manuscript", "confidential", "copy",
+	"do not distribute", "draft", "for peer review", "for peer review only",
+	"for review only", "internal use only", "not for distribution",
+	"not peer reviewed", "preprint", "proof", "sample", "submitted manuscript",
+	"uncorrected proof", "under review", "watermark",
+}
+
+// stampPrefixes are the prefixes, normalized, of download watermarks
+// and notices of publishers.
+var stampPrefixes = []string{
+	"authorized licensed use limited to", "brought to you by", "downloaded by",
+	"downloaded from", "this copy is for", "licensed to",
+	"this article has been accepted for publication",
+	"this is an accepted manuscript", "this is a preprint",
+}
+
+// isStamp returns true if s is the text of a stamp or a watermark,
+// like DRAFT or Downloaded from.
+func isStamp(s string) bool {
+	n := normalized(s)
+	if slices.Contains(stampTexts, n) {
+		return true
+	}
+	for _, prefix := range stampPrefixes {
+		if strings.HasPrefix(n, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// blacklisted returns true if s is a phrase of the blacklist of
+// WithBlacklist, the same text or, for entries ending with *,
+// starting with the text, ignoring case and punctuation.
+func (e *Extractor) blacklisted(s string) bool {
+	if len(e.blacklist) == 0 {
+		return false
+	}
+	n := normalized(s)
+	for _, entry := range e.blacklist {
+		if prefix, ok := strings.CutSuffix(entry, "*"); ok {
+			if prefix != "" && strings.HasPrefix(n, prefix) {
+				return true
+			}
+		} else if n == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// urlPattern matches URLs, with a scheme or www, and domain names
+// of the common top level domains, like example.org/papers.
+var urlPattern = regexp.MustCompile(`(?i)^(?:(?:https?|ftp)://\S+|www\.\S+|(?:[a-z0-9-]+\.)+(?:com|edu|gov|info|int|io|net|org)(?:/\S*)?)$`)
+
+// emailPattern matches email addresses, with an optional Email: label.
+var emailPattern = regexp.MustCompile(`(?i)^(?:e-?mail:?\s*)?[\w.+-]+@[\w-]+(?:\.[\w-]+)+$`)
+
+// months matches the names, and the abbreviations, of months.
+const months = `(?:jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)\.?`
+
+// datePattern matches dates, like 2021-03-04, 04/03/2021, 4.3.2021,
+// March 4, 2021, 4 March 2021 or March 2021, with an optional weekday
+// or Date: label.
+var datePattern = regexp.MustCompile(`(?i)^(?:date:?\s*)?(?:(?:mon|tues|wednes|thurs|fri|satur|sun)day,?\s+)?(?:` +
+	`\d{4}[-/.]\d{1,2}[-/.]\d{1,2}|\d{1,2}[-/.]\d{1,2}[-/.]\d{2,4}|` +
+	months + `\s+\d{1,2}(?:st|nd|rd|th)?,?\s+\d{4}|\d{1,2}(?:st|nd|rd|th)?\s+(?:of\s+)?` + months + `,?\s+\d{4}|` +
+	months + `,?\s+\d{4})$`)
+
+// localMonths matches the names, and the abbreviations, of months
+// of languages other than English by language code.
+var localMonths = map[string]string{
+	"de": `(?:jan(?:uar)?|jän(?:ner)?|feb(?:ruar)?|mär(?:z)?|apr(?:il)?|mai|juni?|juli?|aug(?:ust)?|sep(?:t(?:ember)?)?|okt(?:ober)?|nov(?:ember)?|dez(?:ember)?)\.?`,
+	"fr": `(?:janv(?:ier)?|févr(?:ier)?|mars|avr(?:il)?|mai|juin|juil(?:let)?|août|sept(?:embre)?|oct(?:obre)?|nov(?:embre)?|déc(?:embre)?)\.?`,
+	"es": `(?:ene(?:ro)?|feb(?:rero)?|mar(?:zo)?|abr(?:il)?|may(?:o)?|jun(?:io)?|jul(?:io)?|ago(?:sto)?|sep(?:t(?:iembre)?)?|oct(?:ubre)?|nov(?:iembre)?|dic(?:iembre)?)\.?`,
+	"it": `(?:genn(?:aio)?|febbr(?:aio)?|mar(?:zo)?|apr(?:ile)?|magg(?:io)?|giu(?:gno)?|lug(?:lio)?|ago(?:sto)?|sett(?:embre)?|ott(?:obre)?|nov(?:embre)?|dic(?:embre)?)\.?`,
+	"pt": `(?:jan(?:eiro)?|fev(?:ereiro)?|mar(?:ço)?|abr(?:il)?|mai(?:o)?|jun(?:ho)?|jul(?:ho)?|ago(?:sto)?|set(?:embro)?|out(?:ubro)?|nov(?:embro)?|dez(?:embro)?)\.?`,
+	"nl": `(?:jan(?:uari)?|feb(?:ruari)?|maart|mrt|apr(?:il)?|mei|juni?|juli?|aug(?:ustus)?|sep(?:t(?:ember)?)?|okt(?:ober)?|nov(?:ember)?|dec(?:ember)?)\.?`,
+}
+
+// localDatePatterns match the dates of the languages of localMonths,
+// like 4. März 2021, 4 mars 2021, 4 de marzo de 2021 or março de 2021.
+var localDatePatterns = func() map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp)
+	for code, months := range localMonths {
+		patterns[code] = regexp.MustCompile(`(?i)^(?:\d{1,2}(?:\.|er|º)?\s+(?:de\s+)?)?` + months + `(?:\s+de)?,?\s+\d{4}$`)
+	}
+	return patterns
+}()
+
+// shapeOf returns why s is shaped like a URL, an email address or
+// a date, that are never titles even in large fonts, like the banners
+// of web pages printed to pdf, or the empty string if it is not. Dates
+// are in English, or in the language of the locale of WithLocale.
+func (e *Extractor) shapeOf(s string) string {
+	s = strings.TrimSpace(strings.Join(strings.Fields(s), " "))
+	switch {
+	case urlPattern.MatchString(s):
+		return "URL"
+	case emailPattern.MatchString(s):
+		return "email address"
+	case datePattern.MatchString(s):
+		return "date"
+	}
+	if p := localDatePatterns[e.localeCode()]; p != nil && p.MatchString(s) {
+		return "date"
+	}
+	return ""
+}
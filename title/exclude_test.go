@@ -0,0 +1,73 @@
+package title
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestShapeOfDates(t *testing.T) {
+	tests := []struct {
+		locale language.Tag
+		s      string
+		want   string
+	}{
+		{language.Und, "March 4, 2021", "date"},
+		{language.Und, "4 March 2021", "date"},
+		{language.Und, "2021-03-04", "date"},
+		{language.Und, "4. März 2021", ""},
+		{language.German, "4. März 2021", "date"},
+		{language.German, "März 2021", "date"},
+		{language.German, "March 4, 2021", "date"},
+		{language.French, "4 mars 2021", "date"},
+		{language.French, "1er juillet 2020", "date"},
+		{language.Spanish, "4 de marzo de 2021", "date"},
+		{language.Portuguese, "março de 2021", "date"},
+		{language.Italian, "4 marzo 2021", "date"},
+		{language.Dutch, "4 maart 2021", "date"},
+		{language.German, "Mai Tage 2021", ""},
+		{language.German, "Theorie der Systeme", ""},
+		{language.Und, "www.example.org", "URL"},
+	}
+	for _, tt := range tests {
+		e := New(WithLocale(tt.locale))
+		if got := e.shapeOf(tt.s); got != tt.want {
+			t.Errorf("shapeOf(%q) with locale %v = %q, want %q", tt.s, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestIsPageNumber(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"12", true},
+		{"- 12 -", true},
+		{"[3]", true},
+		{"Page 3", true},
+		{"PAGE 3", true},
+		{"3 of 10", true},
+		{"3 / 10", true},
+		{"iv", true},
+		{"XIV", true},
+		{"cxlviii", true},
+		{"Page xii", true},
+		{"Civil", false},
+		{"Vivid", false},
+		{"Mild", false},
+		{"Did", false},
+		{"MIMIC", false},
+		{"DIV", false},
+		{"iiii", false},
+		{"Xiv", false},
+		{"Page", false},
+		{"-", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isPageNumber(tt.s); got != tt.want {
+			t.Errorf("isPageNumber(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,654 @@
+package title
+
+import (
+	"log/slog"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"rsc.io/pdf"
+)
+
+// An Extractor extracts titles of pdf documents.
+// An Extractor is safe for concurrent use.
+type Extractor struct {
+	// spacing multipied by font size determines if
+	// two consecutive letters are in the same word.
+	spacing float64
+
+	// disableWordsCheck toggles the check for words in dictionary.
+	disableWordsCheck bool
+
+	// validator decides if a string is a title. If nil,
+	// the dictionary check is used.
+	validator TitleValidator
+
+	// wordsInDictPercent is the percentage of words in a string
+	// that must be dictionary words for the string to be a valid title.
+	wordsInDictPercent float64
+
+	// scorer ranks the phrases of the title page. If nil, the
+	// phrase with the best score of weights is the title.
+	scorer Scorer
+
+	// decoder transforms pdfs the reader cannot handle.
+	decoder Decoder
+
+	// locale is the language of the titles. It selects the
+	// casing rules used when matching words.
+	locale language.Tag
+
+	// acronyms is a set of acronyms that count as dictionary words.
+	// Acronyms are matched case sensitively.
+	acronyms map[string]bool
+
+	// words is a set of words, in lower case, of word
+	// lists that count as dictionary words.
+	words map[string]bool
+
+	// techWords toggles counting the embedded technical
+	// and scientific terms as dictionary words.
+	techWords bool
+
+	// dicts are the words, in lower case, of the dictionaries
+	// of languages of WithDictionary by language code.
+	dicts map[string]map[string]bool
+
+	// likelyAcronyms toggles counting all caps words of
+	// 2 to 6 letters as dictionary words.
+	likelyAcronyms bool
+
+	// stripQuotes toggles removing the quotation marks
+	// that enclose the whole title.
+	stripQuotes bool
+
+	// subtitleSep separates the title from the subtitle appended to it.
+	// If empty, the subtitle is not appended.
+	subtitleSep string
+
+	// findAuthors toggles extracting the authors below the title.
+	findAuthors bool
+
+	// findAbstract toggles extracting the abstract.
+	findAbstract bool
+
+	// findIDs toggles extracting the identifiers of documents, like DOIs.
+	findIDs bool
+
+	// findYear toggles extracting the publication year.
+	findYear bool
+
+	// findVenue toggles extracting the journal or conference name.
+	findVenue bool
+
+	// findKeywords toggles extracting the keywords.
+	findKeywords bool
+
+	// profile is the profile of documents, or ProfileAuto
+	// to detect it. If empty, the thresholds are not changed.
+	profile string
+
+	// fixed are the thresholds set with WithSpacing, WithPages
+	// and WithDictThreshold, that profiles do not change.
+	fixed struct{ spacing, pages, dictThreshold bool }
+
+	// sizeTolerance is the largest difference of font
+	// sizes of the text runs of a phrase.
+	sizeTolerance float64
+
+	// blacklist are the phrases, normalized, that are never titles.
+	// Entries ending with * are prefixes.
+	blacklist []string
+
+	// titleCase toggles converting all caps titles to title case.
+	titleCase bool
+
+	// textCase is the case of titles, CasePreserve, CaseTitle or
+	// CaseSentence. If set, it overrides titleCase.
+	textCase string
+
+	// skipCovers toggles skipping covers and blank pages
+	// to find the title page.
+	skipCovers bool
+
+	// margin is the height of the top and bottom margins of pages,
+	// as a fraction of the page height. Text in margins is not a title.
+	margin float64
+
+	// weights are the weights of the features in the scores of candidates.
+	weights Weights
+
+	// useDests toggles using the labels of links and named
+	// destinations as titles when the text has none.
+	useDests bool
+
+	// useInfo toggles the /Title of the Info dictionary as a title candidate.
+	useInfo bool
+
+	// useXMP toggles the dc:title of XMP metadata as a title candidate.
+	useXMP bool
+
+	// useOutline toggles the title of the outline as a title candidate.
+	useOutline bool
+
+	// useTags toggles preferring the text tagged as the title
+	// in the structure tree of tagged pdfs.
+	useTags bool
+
+	// repeatHeaderStrip toggles excluding text repeated at the same
+	// position on the following pages, like running headers, from titles.
+	repeatHeaderStrip bool
+
+	// repeatHeaderTitle toggles preferring text repeated at the same
+	// position on the following pages as the title. Running headers
+	// often repeat the short title of papers.
+	repeatHeaderTitle bool
+
+	// pageNum is the page of the title. If 0, the title
+	// is on the first page.
+	pageNum int
+
+	// limits bound the work for a document.
+	limits Limits
+
+	// logger receives debug events about the decisions of the extractor.
+	logger *slog.Logger
+
+	// pages is the number of pages, starting from the title page,
+	// whose phrases are title candidates. If 0, only the title page.
+	pages int
+
+	// maxLen is the maximum length of a title in bytes.
+	// Longer titles are cut at a word boundary. If 0,
+	// titles are not truncated.
+	maxLen int
+
+	// minWords is the minimum number of words of at least 3 letters
+	// of a title. It rejects logos and decorative glyphs in huge fonts.
+	minWords int
+}
+
+// An Option configures an Extractor.
+type Option func(*Extractor)
+
+// New returns an Extractor configured with opts.
+func New(opts ...Option) *Extractor {
+	e := &Extractor{
+		spacing:            0.16,
+		sizeTolerance:      4,
+		wordsInDictPercent: 0.20,
+		maxLen:             80,
+		weights:            DefaultWeights,
+		margin:             0.05,
+		skipCovers:         true,
+		decoder:            Ghostscript{Cmd: "gs"},
+		locale:             language.Und,
+		acronyms:           make(map[string]bool),
+		words:              make(map[string]bool),
+		dicts:              make(map[string]map[string]bool),
+		logger:             slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// WithSpacing sets the spacing coefficient. Multiplied by the
+// font size it determines if two consecutive letters are in the same word.
+// The default is 0.16.
+func WithSpacing(c float64) Option {
+	return func(e *Extractor) {
+		e.spacing = c
+		e.fixed.spacing = true
+	}
+}
+
+// WithDictThreshold sets the minimum fraction of words of a title
+// that must be dictionary words. The default is 0.20.
+func WithDictThreshold(p float64) Option {
+	return func(e *Extractor) {
+		e.wordsInDictPercent = p
+		e.fixed.dictThreshold = true
+	}
+}
+
+// WithDictCheck toggles the check for dictionary words in titles.
+// It is enabled by default.
+func WithDictCheck(enabled bool) Option {
+	return func(e *Extractor) {
+		e.disableWordsCheck = !enabled
+	}
+}
+
+// WithValidator sets the validator that decides if a string is a title,
+// replacing the dictionary check. A nil validator restores it.
+// WithDictCheck(false) disables any validation.
+func WithValidator(v TitleValidator) Option {
+	return func(e *Extractor) {
+		e.validator = v
+	}
+}
+
+// WithScorer sets the scorer that ranks the phrases of the title page.
+// The first ranked phrase is the title. A nil scorer restores the
+// default, the phrase with the best score of the weights of WithWeights.
+func WithScorer(s Scorer) Option {
+	return func(e *Extractor) {
+		e.scorer = s
+	}
+}
+
+// WithGhostscript sets the ghostscript executable used to transform
+// pdfs the reader cannot handle. The default is "gs".
+// It is a shorthand for WithDecoder(Ghostscript{Cmd: path}).
+func WithGhostscript(path string) Option {
+	return WithDecoder(Ghostscript{Cmd: path})
+}
+
+// WithDecoder sets the decoder used to transform pdfs the reader
+// cannot handle. The default is ghostscript. A nil decoder
+// disables the transformation.
+func WithDecoder(d Decoder) Option {
+	return func(e *Extractor) {
+		e.decoder = d
+	}
+}
+
+// WithLocale sets the language of the titles. It selects the casing
+// rules used when matching words. The default is language.Und.
+func WithLocale(tag language.Tag) Option {
+	return func(e *Extractor) {
+		e.locale = tag
+	}
+}
+
+// WithAcronyms adds acronyms that count as dictionary words.
+// Acronyms are matched case sensitively.
+func WithAcronyms(acronyms ...string) Option {
+	return func(e *Extractor) {
+		for _, a := range acronyms {
+			e.acronyms[a] = true
+		}
+	}
+}
+
+// WithWords adds words, like the words of a word list of another
+// language, to the dictionary. Words are matched ignoring case.
+func WithWords(words ...string) Option {
+	return func(e *Extractor) {
+		for _, w := range words {
+			e.words[strings.ToLower(w)] = true
+		}
+	}
+}
+
+// WithTechWords toggles counting the embedded technical and scientific
+// terms, like Kubernetes or genomics, that are not in the dictionary of
+// general English words, as dictionary words, for research papers.
+func WithTechWords(enabled bool) Option {
+	return func(e *Extractor) {
+		e.techWords = enabled
+	}
+}
+
+// WithDictionary adds the words of a word list of the language tag to
+// the dictionaries. Titles with few English words are checked with the
+// dictionary of their language, the language of WithLocale or else the
+// language detected by the trigrams of their words and every language
+// of WithDictionary, since only the languages of the embedded word lists
+// are detected. Word lists of the common words of German, French,
+// Spanish, Italian, Portuguese and Dutch are embedded. Words are also matched by their stems, with the
+// light stemmer of the language that removes its common suffixes.
+// Titles in scripts without an embedded dictionary, like Cyrillic, are
+// checked with the dictionary of WithDictionary in their script.
+func WithDictionary(tag language.Tag, words ...string) Option {
+	return func(e *Extractor) {
+		base, _ := tag.Base()
+		code := base.String()
+		lower := cases.Lower(tag)
+		if e.dicts[code] == nil {
+			e.dicts[code] = make(map[string]bool)
+		}
+		for _, w := range words {
+			lw := lower.String(w)
+			e.dicts[code][lw] = true
+			e.dicts[code][stemOf(lw, code)] = true
+		}
+	}
+}
+
+// WithLikelyAcronyms toggles counting all caps words of
+// 2 to 6 letters as dictionary words in titles with other
+// dictionary words, so that all caps noise is not a title.
+func WithLikelyAcronyms(enabled bool) Option {
+	return func(e *Extractor) {
+		e.likelyAcronyms = enabled
+	}
+}
+
+// WithStripQuotes toggles removing the quotation marks
+// that enclose the whole title.
+func WithStripQuotes(enabled bool) Option {
+	return func(e *Extractor) {
+		e.stripQuotes = enabled
+	}
+}
+
+// WithSubtitle appends the subtitle to the title, separated by sep,
+// like ": ". If the title ends with a colon or a dash, the separator
+// is a space. An empty sep, the default, does not append it.
+func WithSubtitle(sep string) Option {
+	return func(e *Extractor) {
+		e.subtitleSep = sep
+	}
+}
+
+// WithAuthors toggles extracting the names of the authors from the
+// lines below the title, see Result.Authors.
+func WithAuthors(enabled bool) Option {
+	return func(e *Extractor) {
+		e.findAuthors = enabled
+	}
+}
+
+// WithAbstract toggles extracting the abstract, the paragraph
+// after the Abstract heading of the first pages, see Result.Abstract.
+func WithAbstract(enabled bool) Option {
+	return func(e *Extractor) {
+		e.findAbstract = enabled
+	}
+}
+
+// WithIdentifiers toggles extracting the identifiers of documents
+// from their text, see Result.DOI, Result.ArXiv and Result.ISBNs.
+func WithIdentifiers(enabled bool) Option {
+	return func(e *Extractor) {
+		e.findIDs = enabled
+	}
+}
+
+// WithYear toggles extracting the likely publication
+// year of documents, see Result.Year.
+func WithYear(enabled bool) Option {
+	return func(e *Extractor) {
+		e.findYear = enabled
+	}
+}
+
+// WithVenue toggles extracting the name of the journal or
+// the conference of documents, see Result.Venue.
+func WithVenue(enabled bool) Option {
+	return func(e *Extractor) {
+		e.findVenue = enabled
+	}
+}
+
+// WithKeywords toggles extracting the keywords of the Keywords
+// or Index Terms line of the first pages, see Result.Keywords.
+func WithKeywords(enabled bool) Option {
+	return func(e *Extractor) {
+		e.findKeywords = enabled
+	}
+}
+
+// WithCase sets the case of titles to c, CasePreserve, CaseTitle or
+// CaseSentence. Acronyms and words in all caps or with capitals inside,
+// like LaTeX, keep their case. Words are cased with the rules of the
+// locale of WithLocale and title case keeps the small words of its
+// language, if any, in lower case. An empty c, the default, is CasePreserve
+// unless WithTitleCase is set.
+func WithCase(c string) Option {
+	return func(e *Extractor) {
+		e.textCase = c
+	}
+}
+
+// WithProfile sets the profile of documents, ProfilePaper, ProfileSlides,
+// ProfileBook or ProfileReport, or ProfileAuto to detect the profile of
+// each document. A profile sets the spacing, the tolerance of font sizes
+// in phrases, the pages of the candidates and the dictionary threshold,
+// except those set with WithSpacing, WithPages and WithDictThreshold.
+// An empty name, the default, keeps them.
+func WithProfile(name string) Option {
+	return func(e *Extractor) {
+		e.profile = name
+	}
+}
+
+// WithBlacklist adds phrases to the blacklist of phrases that are never
+// titles, like the boilerplate of a site. Phrases match ignoring case
+// and punctuation and phrases ending with * match the start of text.
+// Stamps, like DRAFT or Accepted Manuscript, are always blacklisted.
+func WithBlacklist(phrases ...string) Option {
+	return func(e *Extractor) {
+		for _, p := range phrases {
+			entry := normalized(p)
+			if strings.HasSuffix(strings.TrimSpace(p), "*") {
+				entry += "*"
+			}
+			if entry != "" && entry != "*" {
+				e.blacklist = append(e.blacklist, entry)
+			}
+		}
+	}
+}
+
+// WithTitleCase toggles converting titles in all caps to title case.
+// Acronyms, set with WithAcronyms, and short words that are not
+// dictionary words keep their case.
+func WithTitleCase(enabled bool) Option {
+	return func(e *Extractor) {
+		e.titleCase = enabled
+	}
+}
+
+// WithDests toggles using the labels of links and named
+// destinations as titles when the text has none.
+func WithDests(enabled bool) Option {
+	return func(e *Extractor) {
+		e.useDests = enabled
+	}
+}
+
+// WithSkipCovers toggles skipping blank pages and covers with only
+// images or a few words, among the first pages, to find the title page.
+// It is the default. It has no effect if the page is set with WithPage.
+func WithSkipCovers(enabled bool) Option {
+	return func(e *Extractor) {
+		e.skipCovers = enabled
+	}
+}
+
+// WithMargin sets the height of the top and bottom margins of pages,
+// as a fraction of the page height. Text in the margins, like running
+// headers, footers and page numbers, is not a title. The default is
+// 0.05 and 0 disables it. Page numbers are never titles.
+func WithMargin(frac float64) Option {
+	return func(e *Extractor) {
+		e.margin = frac
+	}
+}
+
+// WithWeights sets the weights of the features of candidates in their
+// scores, the confidence of titles. The position weights also break
+// ties of font sizes. The default is DefaultWeights.
+func WithWeights(w Weights) Option {
+	return func(e *Extractor) {
+		e.weights = w
+	}
+}
+
+// WithInfo toggles using the /Title of the document Info dictionary
+// as a title candidate. It is the title when it is the same as a
+// phrase of the title page or when the page has no better candidate.
+func WithInfo(enabled bool) Option {
+	return func(e *Extractor) {
+		e.useInfo = enabled
+	}
+}
+
+// WithXMP toggles using the dc:title of the XMP metadata of the
+// document as a title candidate, like WithInfo. Publishers often
+// set accurate XMP metadata even when the first page is an image.
+func WithXMP(enabled bool) Option {
+	return func(e *Extractor) {
+		e.useXMP = enabled
+	}
+}
+
+// WithOutline toggles using the title of the outline, the bookmarks,
+// of the document as a title candidate, like WithInfo. The first
+// bookmark is often the title, shortened or with its subtitle, and
+// a phrase of the title page close to it is preferred.
+func WithOutline(enabled bool) Option {
+	return func(e *Extractor) {
+		e.useOutline = enabled
+	}
+}
+
+// WithTags toggles preferring the text tagged as the title in the
+// structure tree of tagged pdfs, the content of the Title element or
+// else of the first H1 element of the title page, over the other
+// phrases. Tagged pdfs, like the accessible pdfs of office suites,
+// mark the title exactly.
+func WithTags(enabled bool) Option {
+	return func(e *Extractor) {
+		e.useTags = enabled
+	}
+}
+
+// WithRepeatHeaderStrip toggles excluding text repeated at the same
+// position on the following pages, like running headers, from titles.
+func WithRepeatHeaderStrip(enabled bool) Option {
+	return func(e *Extractor) {
+		e.repeatHeaderStrip = enabled
+	}
+}
+
+// WithRepeatHeaderTitle toggles preferring text repeated at the same
+// position on the following pages as the title.
+func WithRepeatHeaderTitle(enabled bool) Option {
+	return func(e *Extractor) {
+		e.repeatHeaderTitle = enabled
+	}
+}
+
+// WithPage sets the page of the title. If n is 0,
+// the title is on the first page. Only the phrases of the page
+// are title candidates, overriding WithPages.
+func WithPage(n int) Option {
+	return func(e *Extractor) {
+		e.pageNum = n
+	}
+}
+
+// WithPages sets the number of pages, starting from the title page,
+// whose phrases are title candidates, for documents with covers or
+// blank first pages. The default is 0 and, like 1 or less, it means
+// only the title page. It has no effect if the page is set with WithPage.
+func WithPages(n int) Option {
+	return func(e *Extractor) {
+		e.pages = n
+		e.fixed.pages = true
+	}
+}
+
+// WithMaxLen sets the maximum length of a title in bytes. Longer
+// titles are cut at a word boundary. If n is 0, titles are not
+// truncated. The default is 80.
+func WithMaxLen(n int) Option {
+	return func(e *Extractor) {
+		e.maxLen = n
+	}
+}
+
+// WithMinWords sets the minimum number of words of at least 3 letters
+// of a title. It rejects logos and decorative glyphs in huge fonts.
+func WithMinWords(n int) Option {
+	return func(e *Extractor) {
+		e.minWords = n
+	}
+}
+
+// LevelTrace is the slog level of the events about every phrase
+// of a page, below slog.LevelDebug.
+const LevelTrace = slog.LevelDebug - 4
+
+// WithLogger sets the logger that receives debug events about the
+// decisions of the extractor, like the phrases with the largest fonts,
+// decoder fallbacks and the reasons candidates were rejected.
+// LevelTrace adds all the phrases. The default discards them.
+func WithLogger(l *slog.Logger) Option {
+	return func(e *Extractor) {
+		if l == nil {
+			l = slog.New(slog.DiscardHandler)
+		}
+		e.logger = l
+	}
+}
+
+// Limits bound the work of an Extractor for a document so that
+// pathological pdfs cannot exhaust memory or CPU.
+// A zero field means no limit.
+type Limits struct {
+	// MaxPages is the maximum number of pages scanned.
+	MaxPages int
+
+	// MaxTextRuns is the maximum number of text runs of a page
+	// assembled into phrases. The pdf reader still parses
+	// the whole content of the page.
+	MaxTextRuns int
+
+	// MaxPhraseLen is the maximum length of a phrase in bytes.
+	// Longer text is dropped.
+	MaxPhraseLen int
+
+	// MaxDecodedSize is the maximum size in bytes of a pdf
+	// transformed by the decoder.
+	MaxDecodedSize int64
+}
+
+// WithLimits sets the limits of the work for a document.
+// The default is no limits.
+func WithLimits(l Limits) Option {
+	return func(e *Extractor) {
+		e.limits = l
+	}
+}
+
+// numPage returns the number of pages of doc to scan.
+func (e *Extractor) numPage(doc *pdf.Reader) int {
+	n := doc.NumPage()
+	if e.limits.MaxPages > 0 {
+		n = min(n, e.limits.MaxPages)
+	}
+	return n
+}
+
+// lastPage returns the last page a title extraction may read.
+// Running headers are searched in the 3 pages after the title pages
+// and the title page may follow covers.
+func (e *Extractor) lastPage() int {
+	pages := e.pages
+	if e.profile != "" {
+		pages = max(pages, profiles[ProfileBook].pages)
+	}
+	if e.pageNum > 0 {
+		pages = 1
+	}
+	last := max(1, e.pageNum) + max(1, pages) - 1
+	if e.skipCovers && e.pageNum <= 0 {
+		last += maxCoverPages - 1
+	}
+	if e.repeatHeaderStrip || e.repeatHeaderTitle {
+		last += repeatPages
+	}
+	if e.findAbstract || e.findKeywords {
+		last += abstractPages - 1
+	}
+	if e.findIDs {
+		last += isbnPages - 1
+	}
+	return last
+}
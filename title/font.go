@@ -0,0 +1,87 @@
+package title
+
+import "strings"
+
+// styleWeights are the numeric weights, like those of CSS, of the
+// abbreviated styles of font names, like Arial-Bd or NimbusRomNo9L-Medi.
+var styleWeights = map[string]int{
+	"bd":   700,
+	"bdit": 700,
+	"blk":  900,
+	"hv":   900,
+	"sb":   600,
+	"demi": 600,
+	"medi": 700,
+}
+
+// fontWeightNames are the weight names of font names, in lower case, with
+// their numeric weights. Compound names come first so that "semibold"
+// is not taken for "bold".
+var fontWeightNames = []struct {
+	name   string
+	weight int
+}{
+	{"extrabold", 800},
+	{"ultrabold", 800},
+	{"semibold", 600},
+	{"demibold", 600},
+	{"black", 900},
+	{"heavy", 900},
+	{"bold", 700},
+	{"medium", 500},
+	{"light", 300},
+	{"thin", 100},
+}
+
+// boldTeXFonts are the prefixes of the names of the bold fonts of TeX,
+// like cmbx10 of Computer Modern.
+var boldTeXFonts = []string{"cmbx", "cmb1", "sfbx", "ecbx"}
+
+// fontWeight guesses the weight of font from the conventions of font
+// names, from 100 for thin to 900 for black, 400 for regular fonts.
+// Subset prefixes, like ABCDEF+, are ignored and the style follows
+// the last dash or comma, like Times-Bold or the synthetic Arial,Bold.
+func fontWeight(font string) int {
+	name := font
+	if i := strings.IndexByte(name, '+'); i == 6 {
+		name = name[i+1:]
+	}
+	name = strings.ToLower(name)
+	for _, prefix := range boldTeXFonts {
+		if strings.HasPrefix(name, prefix) {
+			return 700
+		}
+	}
+
+	style := name
+	if i := strings.LastIndexAny(name, "-,"); i >= 0 {
+		style = name[i+1:]
+		if w, ok := styleWeights[style]; ok {
+			return w
+		}
+	}
+	for _, wn := range fontWeightNames {
+		if strings.Contains(style, wn.name) {
+			return wn.weight
+		}
+	}
+	return 400
+}
+
+// fontFamily returns the family of font, its name in lower case
+// without the subset prefix and the style, like times for Times-Bold.
+func fontFamily(font string) string {
+	name := font
+	if i := strings.IndexByte(name, '+'); i == 6 {
+		name = name[i+1:]
+	}
+	if i := strings.IndexAny(name, "-,"); i >= 0 {
+		name = name[:i]
+	}
+	return strings.ToLower(name)
+}
+
+// isBold returns true if the font name suggests a bold font.
+func isBold(font string) bool {
+	return fontWeight(font) >= 600
+}
@@ -0,0 +1,70 @@
+package title
+
+import (
+	"strings"
+	"unicode"
+)
+
+// edgePunct are the punctuation marks around words.
+const edgePunct = `"'“”‘’«»()[]{}.,;:!?`
+
+// isGarbled returns true if s looks like the text of a font whose
+// codes are not mapped to the right letters, like 7KH 4XLFN %URZQ
+// for The Quick Brown. Such text has words mixing letters with digits
+// and symbols, few dictionary words, even if the dictionary check is
+// disabled, or letters of the Unicode private use area.
+func (e *Extractor) isGarbled(s string) bool {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return false
+	}
+	chars, private := 0, 0
+	for _, r := range s {
+		if unicode.Is(unicode.Co, r) {
+			private++
+		}
+		if !unicode.IsSpace(r) {
+			chars++
+		}
+	}
+	if 3*private >= chars {
+		return true
+	}
+	if oddRatio(s) < 1.0/3 {
+		return false
+	}
+	ratio, n := e.dictRatio(s)
+	return n == 0 || ratio < 0.3
+}
+
+// oddRatio returns the fraction of the words of s that are odd words.
+func oddRatio(s string) float64 {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return 0
+	}
+	odd := 0
+	for _, w := range words {
+		if isOddWord(strings.Trim(w, edgePunct)) {
+			odd++
+		}
+	}
+	return float64(odd) / float64(len(words))
+}
+
+// isOddWord returns true if w has letters and digits, symbols or
+// punctuation other than hyphens and apostrophes. Words of titles
+// with digits, like GPT4 or 3D, are few.
+func isOddWord(w string) bool {
+	letters, others := false, false
+	for _, r := range w {
+		switch {
+		case unicode.IsLetter(r) || unicode.Is(unicode.M, r):
+			letters = true
+		case r == '-' || r == '\'' || r == '’':
+		case unicode.IsDigit(r) || unicode.IsSymbol(r) || unicode.IsPunct(r):
+			others = true
+		}
+	}
+	return letters && others
+}
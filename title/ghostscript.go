@@ -0,0 +1,113 @@
+package title
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// A Decoder transforms a pdf that the reader cannot handle, usually
+// because of compressed object streams, to one that it can.
+// Decode returns the transformed pdf and its size.
+type Decoder interface {
+	Decode(ctx context.Context, path string) (io.ReaderAt, int64, error)
+}
+
+// DecoderFunc adapts a function to a Decoder.
+type DecoderFunc func(ctx context.Context, path string) (io.ReaderAt, int64, error)
+
+// Decode calls f(ctx, path).
+func (f DecoderFunc) Decode(ctx context.Context, path string) (io.ReaderAt, int64, error) {
+	return f(ctx, path)
+}
+
+// Ghostscript is a Decoder that runs ghostscript to produce
+// a deflated, uncompressed pdf of the first pages.
+type Ghostscript struct {
+	// Cmd points to the ghoscript executable.
+	Cmd string
+
+	// LastPage is the last page converted. If 0, only the first
+	// page is converted. The extractor sets it to the last page
+	// it needs when it is 0.
+	LastPage int
+
+	// MaxSize is the maximum size of the output in bytes.
+	// If 0, the output is not limited.
+	MaxSize int64
+}
+
+// Decode runs ghostscript on path. Ghostscript is killed if ctx is done
+// before it completes.
+func (g Ghostscript) Decode(ctx context.Context, fname string) (io.ReaderAt, int64, error) {
+	initial := int64(10 * 1024 * 1024)
+	if g.MaxSize > 0 {
+		initial = min(initial, g.MaxSize)
+	}
+	fout := bytes.NewBuffer(make([]byte, 0, initial))
+
+	args := []string{
+		"-dNOPAUSE",
+		"-dBATCH",
+		"-dSAFER",
+		"-dQUIET",
+		"-sDEVICE=pdfwrite",
+		"-sOutputFile=-",
+		"-dFirstPage=1",
+		fmt.Sprintf("-dLastPage=%d", max(1, g.LastPage)),
+		fname,
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancelFunc()
+
+	cmd := exec.CommandContext(ctx, g.Cmd, args...)
+	lw := &limitedWriter{w: fout, n: g.MaxSize}
+	cmd.Stdout = fout
+	if g.MaxSize > 0 {
+		cmd.Stdout = lw
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, 0, fmt.Errorf("failed to transform %q: %w", fname, err)
+	}
+	if err := cmd.Wait(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		} else if lw.over {
+			err = errOutputLimit
+		}
+		return nil, 0, fmt.Errorf("failed to transform %q: %w", fname, err)
+	}
+	return bytes.NewReader(fout.Bytes()), int64(fout.Len()), nil
+}
+
+// errOutputLimit is returned by a limitedWriter over its limit.
+var errOutputLimit = errors.New("output over the size limit")
+
+// A limitedWriter is an io.Writer for the output of ghostscript
+// that writes to w at most n bytes, see Ghostscript.MaxSize.
+type limitedWriter struct {
+	w io.Writer
+
+	// n is the number of bytes left.
+	n int64
+
+	// over is set by the first write over the limit.
+	over bool
+}
+
+// Write writes p to w if it fits in the limit, else it
+// writes nothing, sets over and returns errOutputLimit.
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.n {
+		l.over = true
+		return 0, errOutputLimit
+	}
+	n, err := l.w.Write(p)
+	l.n -= int64(n)
+	return n, err
+}
@@ -0,0 +1,71 @@
+package title
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Hunspell is a TitleValidator that checks the words of titles with
+// hunspell, whose dictionaries know the inflections of words, in any
+// language with an installed dictionary.
+type Hunspell struct {
+	// Cmd points to the hunspell executable.
+	// If empty, it is hunspell.
+	Cmd string
+
+	// Dicts are the names of the dictionaries, like en_US or de_DE.
+	// If empty, hunspell uses the dictionary of the locale.
+	Dicts []string
+
+	// Threshold is the minimum fraction of the words
+	// of a title that are correct, like WithDictThreshold.
+	Threshold float64
+}
+
+// Valid returns true if at least the Threshold of the words of s are
+// correct words of the dictionaries. Titles are not valid if hunspell
+// fails, like when it is missing or a dictionary is not installed.
+func (h Hunspell) Valid(s string) bool {
+	ok, err := h.Check(s)
+	return ok && err == nil
+}
+
+// Check is like Valid but also returns the error of hunspell.
+func (h Hunspell) Check(s string) (bool, error) {
+	words := wordsExtractor.FindAllString(s, -1)
+	if len(words) == 0 {
+		return false, nil
+	}
+	wrong, err := h.misspelled(words)
+	if err != nil {
+		return false, fmt.Errorf("hunspell: %w", err)
+	}
+	return float64(len(words)-wrong)/float64(len(words)) >= h.Threshold, nil
+}
+
+// misspelled returns the number of words that
+// are not in the dictionaries of hunspell.
+func (h Hunspell) misspelled(words []string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	args := []string{"-l"}
+	if len(h.Dicts) > 0 {
+		args = append(args, "-d", strings.Join(h.Dicts, ","))
+	}
+	cmd := exec.CommandContext(ctx, cmp.Or(h.Cmd, "hunspell"), args...)
+	cmd.Stdin = strings.NewReader(strings.Join(words, "\n") + "\n")
+	out, err := cmd.Output()
+	if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+		return 0, fmt.Errorf("%v: %s", err, bytes.TrimSpace(ee.Stderr))
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(bytes.Fields(out)), nil
+}
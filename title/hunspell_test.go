@@ -0,0 +1,61 @@
+package title
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// fakeHunspell writes a shell script that acts as hunspell -l
+// with script and returns its path.
+func fakeHunspell(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell")
+	}
+	cmd := filepath.Join(t.TempDir(), "hunspell")
+	if err := os.WriteFile(cmd, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestHunspell(t *testing.T) {
+	// prints the words that are not Distributed or Systems.
+	h := Hunspell{Cmd: fakeHunspell(t, "grep -v -x -e Distributed -e Systems; exit 0"), Threshold: 0.5}
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"Distributed Systems", true},
+		{"Distributed Xqzt", true},
+		{"Xqzt Wvbk Systems", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		ok, err := h.Check(tt.s)
+		if err != nil || ok != tt.want {
+			t.Errorf("Check(%q) = %v, %v, want %v", tt.s, ok, err, tt.want)
+		}
+		if got := h.Valid(tt.s); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestHunspellFails(t *testing.T) {
+	h := Hunspell{Cmd: fakeHunspell(t, "echo \"Can't open affix or dictionary files\" >&2; exit 1"), Threshold: 0.5}
+	if ok, err := h.Check("Distributed Systems"); ok || err == nil {
+		t.Errorf("Check = %v, %v, want false and an error", ok, err)
+	}
+	if h.Valid("Distributed Systems") {
+		t.Error("Valid = true when hunspell fails")
+	}
+	if New(WithValidator(h)).valid("Distributed Systems") {
+		t.Error("valid = true when hunspell fails")
+	}
+	if (Hunspell{Cmd: filepath.Join(t.TempDir(), "missing")}).Valid("Distributed Systems") {
+		t.Error("Valid = true when hunspell is missing")
+	}
+}
@@ -0,0 +1,142 @@
+package title
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"rsc.io/pdf"
+)
+
+// doiPattern matches DOIs, the prefix 10. and a registrant code
+// followed by a slash and the suffix. Suffixes may have any printable
+// letter but in practice they are the letters recommended by Crossref.
+var doiPattern = regexp.MustCompile(`\b10\.\d{4,9}/[-._;()/:<>A-Za-z0-9]+`)
+
+// doiTrailing are the letters that end sentences and
+// enclose DOIs in text, and not a part of them.
+const doiTrailing = ".,;:)>"
+
+// arXivPattern matches arXiv identifiers after the arXiv: prefix or in
+// arxiv.org links, with the version if any. New identifiers, since 2007,
+// are the year and month and a sequence number, like 2104.12345v2, and
+// old ones the archive and a number, like hep-th/9901001.
+var arXivPattern = regexp.MustCompile(`(?i:\barxiv:\s*|\barxiv\.org/(?:abs|pdf)/)(\d{4}\.\d{4,5}(?:v\d+)?|[a-z-]+(?:\.[A-Z]{2})?/\d{7}(?:v\d+)?)`)
+
+// isbnPattern matches ISBNs after the ISBN prefix, 10 or 13 digits,
+// the last one of ISBN-10 may be X, separated by hyphens or spaces.
+var isbnPattern = regexp.MustCompile(`\bISBN(?:-1[03])?[:\s]*((?:\d[-‐– ]?){9,12}[\dXx])`)
+
+// isbnPages is the number of pages, from the title page,
+// searched for ISBNs. Copyright pages follow title pages.
+const isbnPages = 3
+
+// doiOf returns the first DOI in texts, or the empty string if there is none.
+func doiOf(texts []string) string {
+	for _, s := range texts {
+		if doi := doiPattern.FindString(s); doi != "" {
+			// parentheses are trimmed only if they are not balanced,
+			// like in 10.1002/(SICI)1097-4571.
+			for strings.ContainsAny(doi[len(doi)-1:], doiTrailing) {
+				if doi[len(doi)-1] == ')' && strings.Count(doi, "(") >= strings.Count(doi, ")") {
+					break
+				}
+				doi = doi[:len(doi)-1]
+			}
+			return doi
+		}
+	}
+	return ""
+}
+
+// arXivOf returns the first arXiv identifier in texts,
+// or the empty string if there is none.
+func arXivOf(texts []string) string {
+	for _, s := range texts {
+		if m := arXivPattern.FindStringSubmatch(s); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// isbnsOf returns the valid ISBNs in texts, without separators and
+// in the order they appear. Books list an ISBN for each format.
+func isbnsOf(texts []string) []string {
+	var isbns []string
+	for _, s := range texts {
+		for _, m := range isbnPattern.FindAllStringSubmatch(s, -1) {
+			digits := strings.ToUpper(strings.Map(func(r rune) rune {
+				if r >= '0' && r <= '9' || r == 'X' || r == 'x' {
+					return r
+				}
+				return -1
+			}, m[1]))
+			// a number after the ISBN may be matched as a part of it.
+			var isbn string
+			switch {
+			case len(digits) >= 13 && validISBN13(digits[:13]):
+				isbn = digits[:13]
+			case len(digits) >= 10 && validISBN10(digits[:10]):
+				isbn = digits[:10]
+			}
+			if isbn != "" && !slices.Contains(isbns, isbn) {
+				isbns = append(isbns, isbn)
+			}
+		}
+	}
+	return isbns
+}
+
+// validISBN10 returns true if the check digit of the ISBN-10 s is right.
+// The sum of the digits weighted from 10 down to 1 is a multiple of 11
+// and X is 10.
+func validISBN10(s string) bool {
+	sum := 0
+	for i, r := range s {
+		d := int(r - '0')
+		if r == 'X' {
+			if i != 9 {
+				return false
+			}
+			d = 10
+		}
+		sum += (10 - i) * d
+	}
+	return sum%11 == 0
+}
+
+// validISBN13 returns true if the ISBN-13 s starts with 978 or 979 and
+// its check digit is right. The sum of the digits weighted alternately
+// by 1 and 3 is a multiple of 10.
+func validISBN13(s string) bool {
+	if !strings.HasPrefix(s, "978") && !strings.HasPrefix(s, "979") || strings.Contains(s, "X") {
+		return false
+	}
+	sum := 0
+	for i, r := range s {
+		sum += int(r-'0') * (1 + 2*(i%2))
+	}
+	return sum%10 == 0
+}
+
+// idsOf sets the identifiers of doc in res from the text of the title
+// page. arXiv identifiers are usually in the side stamp of preprints,
+// rotated text in the left margin. ISBNs are searched in the pages
+// before the title page, like covers, and the pages after it.
+func (e *Extractor) idsOf(doc *pdf.Reader, res *Result) error {
+	_, num, err := e.titlePage(doc)
+	if isNoText(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	texts := e.textOfPages(doc, num, 1)
+	res.DOI = doiOf(texts)
+	res.ArXiv = arXivOf(texts)
+	res.ISBNs = isbnsOf(e.textOfPages(doc, 1, num+isbnPages-1))
+	if res.DOI != "" || res.ArXiv != "" || len(res.ISBNs) > 0 {
+		e.logger.Debug("identifiers", "doi", res.DOI, "arxiv", res.ArXiv, "isbn", res.ISBNs)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package title
+
+import (
+	"regexp"
+	"strings"
+
+	"rsc.io/pdf"
+)
+
+// keywordsHeading matches the headings of keywords, like Keywords:
+// or Index Terms—, and the punctuation that follows them.
+var keywordsHeading = regexp.MustCompile(`\b(?:Keywords|KEYWORDS|Key words|Key Words|Index Terms|INDEX TERMS)\b[\s.:—–-]*`)
+
+// keywordSeparators split keyword lists.
+var keywordSeparators = regexp.MustCompile(`\s*(?:[,;·•]|\s[—–]\s)\s*`)
+
+// maxKeywordWords is the maximum number of words of keywords.
+// Longer parts of keyword lists are sentences that follow them.
+const maxKeywordWords = 8
+
+// keywordsOf returns the keywords of the Keywords or Index Terms line
+// of the first pages of doc, from the title page, or nil if there is
+// none. Keywords are separated by commas, semicolons, bullets or dashes.
+func (e *Extractor) keywordsOf(doc *pdf.Reader) ([]string, error) {
+	_, num, err := e.titlePage(doc)
+	if isNoText(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	list := afterHeading(e.textOfPages(doc, num, abstractPages), keywordsHeading)
+	var keywords []string
+	for _, kw := range keywordSeparators.Split(strings.Join(strings.Fields(list), " "), -1) {
+		kw = strings.TrimRight(kw, ".")
+		n := len(strings.Fields(kw))
+		if n > maxKeywordWords {
+			break
+		}
+		if n > 0 {
+			keywords = append(keywords, kw)
+		}
+	}
+	if len(keywords) > 0 {
+		e.logger.Debug("keywords", "keywords", keywords)
+	}
+	return keywords, nil
+}
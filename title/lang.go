@@ -0,0 +1,192 @@
+package title
+
+import (
+	"embed"
+	"maps"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+var (
+	// langsFS has the word lists of languages, the most frequent words
+	// and the common words of titles, one per line, named by the ISO
+	// 639-1 code of their language, like de.txt.
+	//go:embed langs/*.txt
+	langsFS embed.FS
+
+	// languages are the languages of the word lists of langsFS by their
+	// code, built on first use.
+	languages     map[string]*wordList
+	languagesOnce sync.Once
+)
+
+// english is the language of the embedded dictionary.
+const english = "en"
+
+// unknownLanguage is the code of the language of texts whose language
+// is not detected, see detectLanguage.
+const unknownLanguage = "unknown"
+
+// minLanguageHits is the minimum number of the words of a text in the
+// word list of the detected language, see detectLanguage. Trigrams
+// alone always find a most likely language, even for English acronyms
+// or the words of other scripts.
+const minLanguageHits = 1
+
+// minEnglishRatio is the ratio of English dictionary words below
+// which the language of a text is detected, see dictRatio.
+const minEnglishRatio = 0.5
+
+// A wordList is the word list of a language, with the stems of its
+// words, and the frequencies of the trigrams of its words for
+// detecting the language of text.
+type wordList struct {
+	words    map[string]bool
+	trigrams map[string]float64
+	total    float64
+}
+
+// newWordList returns the word list of words, one per line.
+func newWordList(code, words string) *wordList {
+	lower := cases.Lower(language.Make(code))
+	wl := &wordList{words: make(map[string]bool), trigrams: make(map[string]float64)}
+	for w := range strings.Lines(words) {
+		if w = lower.String(strings.TrimSpace(w)); w != "" {
+			wl.words[w] = true
+			wl.words[stemOf(w, code)] = true
+			for _, t := range trigramsOf(w) {
+				wl.trigrams[t]++
+				wl.total++
+			}
+		}
+	}
+	return wl
+}
+
+// languageLists returns the word lists of the languages of langsFS.
+func languageLists() map[string]*wordList {
+	languagesOnce.Do(func() {
+		languages = make(map[string]*wordList)
+		entries, _ := langsFS.ReadDir("langs")
+		for _, entry := range entries {
+			data, err := langsFS.ReadFile(path.Join("langs", entry.Name()))
+			if err != nil {
+				continue
+			}
+			code := strings.TrimSuffix(entry.Name(), ".txt")
+			languages[code] = newWordList(code, string(data))
+		}
+	})
+	return languages
+}
+
+// trigramsOf returns the trigrams of the word w, padded with
+// spaces so that the starts and the ends of words count.
+func trigramsOf(w string) []string {
+	r := []rune(" " + w + " ")
+	var trigrams []string
+	for i := 0; i+3 <= len(r); i++ {
+		trigrams = append(trigrams, string(r[i:i+3]))
+	}
+	return trigrams
+}
+
+// detectLanguage returns the code of the language of the word lists
+// whose trigrams are the most likely in the words of s, or
+// unknownLanguage if less than minLanguageHits words of s are in
+// its word list, like if s has no words.
+func detectLanguage(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) == 0 {
+		return unknownLanguage
+	}
+	lists := languageLists()
+	vocabulary := 0.0
+	for _, wl := range lists {
+		vocabulary += float64(len(wl.trigrams))
+	}
+	best, bestScore := english, math.Inf(-1)
+	for code, wl := range lists {
+		// add-one smoothing so that unknown trigrams
+		// do not rule out a language.
+		score := 0.0
+		for _, w := range words {
+			for _, t := range trigramsOf(w) {
+				score += math.Log((wl.trigrams[t] + 1) / (wl.total + vocabulary))
+			}
+		}
+		if score > bestScore || score == bestScore && code < best {
+			best, bestScore = code, score
+		}
+	}
+	hits := 0
+	for _, w := range words {
+		if wl := lists[best]; wl != nil && (wl.words[w] || wl.words[stemOf(w, best)]) {
+			hits++
+		}
+	}
+	if hits < minLanguageHits {
+		return unknownLanguage
+	}
+	return best
+}
+
+// localeCode returns the code of the language of the locale of
+// WithLocale, or English, the default, if it is not set.
+func (e *Extractor) localeCode() string {
+	if base, conf := e.locale.Base(); conf != language.No && e.locale != language.Und {
+		return base.String()
+	}
+	return english
+}
+
+// languageOf returns the code of the language of the text s, the
+// language of the locale of WithLocale or else the detected language,
+// which may be unknownLanguage.
+func (e *Extractor) languageOf(s string) string {
+	if base, conf := e.locale.Base(); conf != language.No && e.locale != language.Und {
+		return base.String()
+	}
+	return detectLanguage(s)
+}
+
+// languagesOf returns the codes of the languages whose dictionaries
+// check the text s: the language of languageOf, unless it is unknown,
+// and, without a locale, the languages of WithDictionary too, since
+// those without an embedded word list are never detected.
+func (e *Extractor) languagesOf(s string) []string {
+	var codes []string
+	if code := e.languageOf(s); code != unknownLanguage {
+		codes = append(codes, code)
+	}
+	if _, conf := e.locale.Base(); conf != language.No && e.locale != language.Und {
+		return codes
+	}
+	for _, code := range slices.Sorted(maps.Keys(e.dicts)) {
+		if !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// inLanguage returns true if w, or its stem with the stemmer of the
+// language code, is a word, or the stem of a word, of the word list of
+// the language or of the words of WithDictionary for it.
+func (e *Extractor) inLanguage(w, code string) bool {
+	lw := cases.Lower(language.Make(code)).String(w)
+	stem := stemOf(lw, code)
+	if wl := languageLists()[code]; wl != nil && (wl.words[lw] || wl.words[stem]) {
+		return true
+	}
+	return e.dicts[code][lw] || e.dicts[code][stem]
+}
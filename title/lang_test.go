@@ -0,0 +1,76 @@
+package title
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"Untersuchungen zur Geschichte der Stadt Wien", "de"},
+		{"Histoire de la langue française", "fr"},
+		{"Historia de la lengua española", "es"},
+		{"Storia della lingua italiana", "it"},
+		{"História da língua portuguesa", "pt"},
+		{"Geschiedenis van de Nederlandse taal", "nl"},
+		{"BERT NLP GPT", unknownLanguage},
+		{"BERT for NLP Tasks", unknownLanguage},
+		{"Привет мир", unknownLanguage},
+		{"Qxzv Kwpt Jjrm", unknownLanguage},
+		{"", unknownLanguage},
+	}
+	for _, tt := range tests {
+		if got := detectLanguage(tt.s); got != tt.want {
+			t.Errorf("detectLanguage(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestLanguageOf(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		s    string
+		want string
+	}{
+		{"detected", nil, "Untersuchungen zur Geschichte der Stadt Wien", "de"},
+		{"unknown", nil, "BERT for NLP Tasks", unknownLanguage},
+		{"locale", []Option{WithLocale(language.German)}, "BERT for NLP Tasks", "de"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.opts...).languageOf(tt.s); got != tt.want {
+				t.Errorf("languageOf(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDictCheckUndetectedLanguage(t *testing.T) {
+	// Swedish has no embedded word list, so it is never detected.
+	sv := WithDictionary(language.Swedish, "svensk", "historia", "och", "kultur")
+	tests := []struct {
+		name string
+		opts []Option
+		want bool
+	}{
+		{"no dictionary", nil, false},
+		{"dictionary", []Option{sv}, true},
+		{"dictionary and locale", []Option{sv, WithLocale(language.Swedish)}, true},
+		{"dictionary and other locale", []Option{sv, WithLocale(language.German)}, false},
+	}
+	const s = "Svensk historia och kultur"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(append(tt.opts, WithDictThreshold(1))...)
+			if got := e.dictCheck(s); got != tt.want {
+				ratio, n := e.dictRatio(s)
+				t.Errorf("dictCheck(%q) = %v, want %v (ratio %.2f of %d words)", s, got, tt.want, ratio, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package title
+
+import (
+	"encoding/xml"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+
+	"rsc.io/pdf"
+)
+
+// Sources of title candidates.
+const (
+	// SourcePage is the text of a page.
+	SourcePage = "page"
+
+	// SourceInfo is the /Title of the document Info dictionary.
+	SourceInfo = "info"
+
+	// SourceXMP is the dc:title of the XMP metadata of the document.
+	SourceXMP = "xmp"
+
+	// SourceOutline is the title of the outline, the bookmarks,
+	// of the document, see outlineOfDoc.
+	SourceOutline = "outline"
+
+	// SourceTags is the text tagged as the title in the structure
+	// tree of tagged pdfs, see taggedTitle.
+	SourceTags = "tags"
+)
+
+// maxXMPSize is the maximum size of XMP metadata read.
+const maxXMPSize = 1 << 20
+
+// dcNamespace is the XML namespace of the Dublin Core
+// properties of XMP metadata.
+const dcNamespace = "http://purl.org/dc/elements/1.1/"
+
+// placeholderExts are the extensions of the file names producers
+// often put in metadata titles instead of the document title.
+var placeholderExts = []string{".doc", ".docx", ".dvi", ".indd", ".odt", ".pdf", ".ppt", ".pptx", ".ps", ".qxd", ".rtf", ".tex", ".txt"}
+
+// placeholderPrefixes are the prefixes, in lower case, of metadata
+// titles set by producers and not by the authors.
+var placeholderPrefixes = []string{"untitled", "microsoft word - ", "microsoft powerpoint - ", "powerpoint presentation"}
+
+// infoOfDoc returns the title and the author of the Info
+// dictionary of doc. They are empty if they are missing.
+func infoOfDoc(doc *pdf.Reader) (tl, author string) {
+	info := doc.Trailer().Key("Info")
+	return metaText(info.Key("Title").Text()), metaText(info.Key("Author").Text())
+}
+
+// xmpOfDoc returns the dc:title and the dc:creator entries of the XMP
+// metadata of doc. They are empty if the metadata is missing or malformed.
+func xmpOfDoc(doc *pdf.Reader) (tl string, creators []string) {
+	md := doc.Trailer().Key("Root").Key("Metadata")
+	if md.Kind() != pdf.Stream {
+		return "", nil
+	}
+	rd := md.Reader()
+	defer rd.Close()
+	return parseXMP(io.LimitReader(rd, maxXMPSize))
+}
+
+// parseXMP returns the dc:title and the dc:creator entries of the XMP
+// packet r. Titles are language alternatives and the first one, usually
+// x-default, is returned. Creators are the items of an ordered array.
+func parseXMP(r io.Reader) (tl string, creators []string) {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	var prop string
+	var text []byte
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space == dcNamespace && (t.Name.Local == "title" || t.Name.Local == "creator") {
+				prop = t.Name.Local
+			}
+			text = text[:0]
+		case xml.CharData:
+			text = append(text, t...)
+		case xml.EndElement:
+			if t.Name.Space == dcNamespace && t.Name.Local == prop {
+				prop = ""
+			}
+			s := metaText(string(text))
+			text = text[:0]
+			if s == "" || prop == "" {
+				continue
+			}
+			switch {
+			case prop == "title" && tl == "":
+				tl = s
+			case prop == "creator":
+				creators = append(creators, s)
+			}
+		}
+	}
+	return
+}
+
+// metaText returns the metadata text s with spaces collapsed.
+func metaText(s string) string {
+	return strings.Join(strings.Fields(normalizeAccents(printable(s))), " ")
+}
+
+// authorOfDoc returns the author of the metadata of doc,
+// from the Info dictionary or the creators of XMP metadata.
+func (e *Extractor) authorOfDoc(doc *pdf.Reader) string {
+	var author string
+	if e.useInfo {
+		_, author = infoOfDoc(doc)
+	}
+	if author == "" && e.useXMP {
+		_, creators := xmpOfDoc(doc)
+		author = strings.Join(creators, ", ")
+	}
+	return author
+}
+
+// metaPhrases returns the metadata titles of doc as phrases
+// without a page, for ranking them with the phrases of the pages.
+func (e *Extractor) metaPhrases(doc *pdf.Reader) []*phrase {
+	var phrases []*phrase
+	if e.useInfo {
+		tl, _ := infoOfDoc(doc)
+		if p := e.metaPhrase(SourceInfo, tl); p != nil {
+			phrases = append(phrases, p)
+		}
+	}
+	if e.useXMP {
+		tl, _ := xmpOfDoc(doc)
+		if p := e.metaPhrase(SourceXMP, tl); p != nil {
+			phrases = append(phrases, p)
+		}
+	}
+	if e.useOutline {
+		if p := e.metaPhrase(SourceOutline, outlineOfDoc(doc)); p != nil {
+			phrases = append(phrases, p)
+		}
+	}
+	if e.useTags {
+		if page, _, err := e.titlePage(doc); err == nil {
+			if p := e.metaPhrase(SourceTags, taggedTitle(doc, page)); p != nil {
+				phrases = append(phrases, p)
+			}
+		}
+	}
+	return phrases
+}
+
+// metaPhrase returns tl as a phrase of source,
+// or nil if tl does not look like a title.
+func (e *Extractor) metaPhrase(source, tl string) *phrase {
+	if isPlaceholder(tl) {
+		if tl != "" {
+			e.logger.Debug("rejected metadata title", "source", source, "text", tl, "reason", "placeholder")
+		}
+		return nil
+	}
+	if shape := e.shapeOf(tl); shape != "" {
+		e.logger.Debug("rejected metadata title", "source", source, "text", tl, "reason", shape)
+		return nil
+	}
+	p := &phrase{source: source, trunc: e.maxLen}
+	p.b.WriteString(tl)
+	p.length = len(tl)
+	return p
+}
+
+// isPlaceholder returns true if the metadata title tl is empty,
+// too short or a placeholder like a file name.
+func isPlaceholder(tl string) bool {
+	l := strings.ToLower(tl)
+	if len(l) < 4 {
+		return true
+	}
+	if slices.Contains(placeholderExts, path.Ext(l)) {
+		return true
+	}
+	for _, prefix := range placeholderPrefixes {
+		if strings.HasPrefix(l, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameText returns true if a and b have the same letters and digits
+// ignoring case, punctuation and spacing.
+func sameText(a, b string) bool {
+	return normalized(a) != "" && normalized(a) == normalized(b)
+}
+
+// normalized returns s in lower case with only letters and digits.
+func normalized(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// agreedPhrase returns the page phrase with the largest font whose text
+// is the same as the text of a metadata phrase, see agrees, or nil if
+// there is none.
+func agreedPhrase(phrases []*phrase) *phrase {
+	var agreed *phrase
+	for _, m := range phrases {
+		if m.source == "" {
+			continue
+		}
+		for _, p := range phrases {
+			if p.source != "" || !agrees(p.String(), m.String(), m.source) {
+				continue
+			}
+			if agreed == nil || p.fontSize > agreed.fontSize {
+				agreed = p
+			}
+		}
+	}
+	return agreed
+}
@@ -0,0 +1,175 @@
+package title
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// A Model is a logistic regression model of the probability that a
+// candidate is the title, fitted by Train to labeled documents. Its
+// features are those of Weights. Models are Scorers that score
+// candidates with the probability and rank them by it.
+type Model struct {
+	// Bias is the intercept of the model.
+	Bias float64 `json:"bias"`
+
+	// Coefficients are the coefficients of the features
+	// by the names of the weights, like font or dict.
+	Coefficients map[string]float64 `json:"coefficients"`
+}
+
+// Example is a labeled document for Train: the candidates of its title
+// page, see Extractor.Candidates, and its title.
+type Example struct {
+	Candidates []Candidate
+	Title      string
+}
+
+// Training parameters of Train.
+const (
+	trainEpochs = 2000
+	trainRate   = 0.5
+	trainL2     = 1e-3
+)
+
+// ErrNoExamples is returned by Train when no example has
+// a candidate with the text of its title.
+var ErrNoExamples = errors.New("no examples with the title among the candidates")
+
+// Train fits a model to the examples. The candidates with the same text
+// as the title of their example, ignoring case and punctuation, are the
+// positive samples and the other page candidates the negative ones.
+// Examples without such a candidate are skipped and used returns the
+// number of the examples used. The positive samples are weighted
+// to balance the few titles with the many other phrases.
+func Train(examples []Example) (m *Model, used int, err error) {
+	var xs [][]float64
+	var ys []float64
+	for _, ex := range examples {
+		largest := largestSize(ex.Candidates)
+		var x [][]float64
+		var y []float64
+		found := false
+		for _, c := range ex.Candidates {
+			if c.Source != SourcePage {
+				continue
+			}
+			label := 0.0
+			if sameText(c.Text, ex.Title) {
+				label, found = 1, true
+			}
+			x = append(x, features(c, largest))
+			y = append(y, label)
+		}
+		if found {
+			xs, ys = append(xs, x...), append(ys, y...)
+			used++
+		}
+	}
+	if used == 0 {
+		return nil, 0, ErrNoExamples
+	}
+
+	pos := 0.0
+	for _, y := range ys {
+		pos += y
+	}
+	posWeight := max(1, (float64(len(ys))-pos)/pos)
+
+	// batch gradient descent of the weighted log loss
+	// with a little L2 regularization.
+	coef := make([]float64, len(weightNames))
+	bias := 0.0
+	for range trainEpochs {
+		grad := make([]float64, len(coef))
+		gbias, total := 0.0, 0.0
+		for i, x := range xs {
+			w := 1.0
+			if ys[i] == 1 {
+				w = posWeight
+			}
+			d := w * (sigmoid(bias+dot(coef, x)) - ys[i])
+			for j := range x {
+				grad[j] += d * x[j]
+			}
+			gbias += d
+			total += w
+		}
+		for j := range coef {
+			coef[j] -= trainRate * (grad[j]/total + trainL2*coef[j])
+		}
+		bias -= trainRate * gbias / total
+	}
+
+	m = &Model{Bias: round(bias), Coefficients: make(map[string]float64)}
+	for j, name := range weightNames {
+		m.Coefficients[name] = round(coef[j])
+	}
+	return m, used, nil
+}
+
+// ReadModel reads a model, written as JSON, from r.
+func ReadModel(r io.Reader) (*Model, error) {
+	var m Model
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("can't read model: %w", err)
+	}
+	for name := range m.Coefficients {
+		if !slices.Contains(weightNames, name) {
+			return nil, fmt.Errorf("can't read model: unknown feature %q", name)
+		}
+	}
+	return &m, nil
+}
+
+// Rank sets the scores of the candidates to the probability of the model
+// that they are the title and ranks them by decreasing score.
+func (m *Model) Rank(cands []Candidate) []Candidate {
+	largest := largestSize(cands)
+	coef := make([]float64, len(weightNames))
+	for j, name := range weightNames {
+		coef[j] = m.Coefficients[name]
+	}
+	for i := range cands {
+		if cands[i].Source == SourcePage {
+			cands[i].Score = round(sigmoid(m.Bias + dot(coef, features(cands[i], largest))))
+		}
+	}
+	slices.SortStableFunc(cands, func(a, b Candidate) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return cands
+}
+
+// largestSize returns the largest font size of cands.
+func largestSize(cands []Candidate) float64 {
+	var largest float64
+	for _, c := range cands {
+		largest = max(largest, c.FontSize)
+	}
+	return largest
+}
+
+// sigmoid returns the logistic function of x, from 0 to 1.
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+// dot returns the dot product of a and b, of at least the length of a.
+func dot(a, b []float64) float64 {
+	var s float64
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
+
+// round returns x rounded to 3 decimals.
+func round(x float64) float64 {
+	return math.Round(x*1000) / 1000
+}
@@ -0,0 +1,116 @@
+package title
+
+import (
+	"cmp"
+	"math"
+	"path"
+	"strings"
+	"sync"
+)
+
+// DefaultPerplexity is the maximum perplexity of NgramModel
+// for titles, see NgramModel.Valid. Titles of papers, with acronyms
+// like BERT or CNN, have a perplexity of 6 to 16 and the garbage of
+// broken fonts and shifted letters of more than 50. 30 is about the
+// geometric mean of the two, so titles of only rare proper nouns,
+// like Tschaikowsky Nguyen at 33 to 43, are rejected.
+const DefaultPerplexity = 30
+
+// NgramModel is a TitleValidator that checks titles with a model of
+// the letters of words, the probabilities of letters after the two
+// previous ones, trained on the words of the embedded dictionaries.
+// Unlike the dictionary check it does not need the exact words of
+// titles, so proper nouns, plurals and new words, like Kubernetes,
+// pass if they are spelled like words, while garbage, like the text
+// of broken fonts, does not.
+type NgramModel struct {
+	// MaxPerplexity is the maximum perplexity of the letters of the
+	// words of titles. If 0, it is DefaultPerplexity.
+	MaxPerplexity float64
+}
+
+// letterModel is the trigram model of the letters of words.
+type letterModel struct {
+	// trigrams and contexts are the counts of the trigrams and of
+	// their first two letters, padded with spaces like trigramsOf.
+	trigrams map[string]float64
+	contexts map[string]float64
+
+	// letters is the number of distinct letters.
+	letters float64
+}
+
+// letterModelOf returns the model of the words, without accents,
+// of the embedded English dictionary and of the word lists of
+// langsFS, built on first use.
+var letterModelOf = sync.OnceValue(func() *letterModel {
+	m := &letterModel{trigrams: make(map[string]float64), contexts: make(map[string]float64)}
+	letters := make(map[rune]bool)
+	add := func(words string) {
+		for _, w := range strings.Fields(withoutAccents(strings.ToLower(words))) {
+			for _, t := range trigramsOf(w) {
+				r := []rune(t)
+				m.trigrams[t]++
+				m.contexts[string(r[:2])]++
+				letters[r[2]] = true
+			}
+		}
+	}
+	add(wordsList)
+	entries, _ := langsFS.ReadDir("langs")
+	for _, entry := range entries {
+		if data, err := langsFS.ReadFile(path.Join("langs", entry.Name())); err == nil {
+			add(string(data))
+		}
+	}
+	m.letters = float64(len(letters))
+	return m
+})
+
+// perplexity returns the perplexity of the letters of the words of s,
+// the inverse of the geometric mean of their probabilities, smoothed
+// with add-one so that unknown trigrams are improbable but possible,
+// and the number of words. Accents are ignored, like in the words
+// of the model, since the word lists of other languages are short.
+// Acronyms, see isAcronym, are not spelled like words and count only
+// if all the words of s are acronyms, like all caps noise.
+func (m *letterModel) perplexity(s string) (float64, int) {
+	words := wordsExtractor.FindAllString(s, -1)
+	var spelled []string
+	for _, w := range words {
+		if !isAcronym(w) {
+			spelled = append(spelled, w)
+		}
+	}
+	if len(spelled) == 0 {
+		spelled = words
+	}
+	logp, n := 0.0, 0
+	for _, w := range spelled {
+		for _, t := range trigramsOf(withoutAccents(strings.ToLower(w))) {
+			r := []rune(t)
+			logp += math.Log((m.trigrams[t] + 1) / (m.contexts[string(r[:2])] + m.letters + 1))
+			n++
+		}
+	}
+	if n == 0 {
+		return math.Inf(1), 0
+	}
+	return math.Exp(-logp / float64(n)), len(words)
+}
+
+// Valid returns true if the perplexity of the letters of the words of s
+// is at most MaxPerplexity. Titles mostly of CJK letters or of the
+// letters of other scripts, see cjkRatio and scriptRatio, have no model
+// and must have at least half common letters or single script words,
+// which does not validate their words.
+func (m NgramModel) Valid(s string) bool {
+	if ratio, _, ok := cjkRatio(s); ok {
+		return ratio >= 0.5
+	}
+	if ratio, _, ok := scriptRatio(s); ok {
+		return ratio >= 0.5
+	}
+	pp, n := letterModelOf().perplexity(s)
+	return n > 0 && pp <= cmp.Or(m.MaxPerplexity, DefaultPerplexity)
+}
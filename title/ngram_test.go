@@ -0,0 +1,39 @@
+package title
+
+import "testing"
+
+func TestNgramModelValid(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"Attention Is All You Need", true},
+		{"BERT for NLP Tasks", true},
+		{"Mask R-CNN", true},
+		{"Kubernetes Scheduling with Reinforcement Learning", true},
+		{"Untersuchungen zur Geschichte der Stadt Wien", true},
+		{"Llanfairpwllgwyngyll Tschaikowsky Nguyen", false},
+		{"QXZV KWPT JJRM", false},
+		{"Tbhf Mfbsojoh gps Jnbhf", false},
+		{"ePTGPFQ WPIPHOXE", false},
+		{"", false},
+	}
+	var m NgramModel
+	for _, tt := range tests {
+		if got := m.Valid(tt.s); got != tt.want {
+			pp, _ := letterModelOf().perplexity(tt.s)
+			t.Errorf("Valid(%q) = %v, want %v (perplexity %.1f, max %d)", tt.s, got, tt.want, pp, DefaultPerplexity)
+		}
+	}
+}
+
+func TestNgramModelMaxPerplexity(t *testing.T) {
+	// 14.6, below the default and above the maximum.
+	s := "BERT for NLP Tasks"
+	if !(NgramModel{}).Valid(s) {
+		t.Errorf("Valid(%q) = false with the default maximum", s)
+	}
+	if (NgramModel{MaxPerplexity: 10}).Valid(s) {
+		t.Errorf("Valid(%q) = true with maximum 10", s)
+	}
+}
@@ -0,0 +1,81 @@
+package title
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"rsc.io/pdf"
+)
+
+// frontMatter are the bookmarks, normalized, of the pages before the
+// title page, like covers, that are skipped to find the bookmark
+// of the title.
+var frontMatter = []string{"cover", "front cover", "title page", "half title", "copyright", "front matter"}
+
+// sectionHeadings are the bookmarks, normalized, of sections
+// that are never the title, like the first section of a paper.
+var sectionHeadings = []string{
+	"abstract", "acknowledgements", "acknowledgments", "appendix", "bibliography",
+	"chapter", "contents", "executive summary", "foreword", "introduction",
+	"list of figures", "list of tables", "overview", "preface", "references",
+	"summary", "table of contents",
+}
+
+// sectionNumber matches the numbers of numbered sections, like 1 or
+// 2.3 or Chapter 1, at the start of bookmarks.
+var sectionNumber = regexp.MustCompile(`(?i)^(?:(?:chapter|part|section)\s+)?(?:\d+(?:\.\d+)*|[ivxlc]+)[.:)]?\s`)
+
+// maxOutlineEntries is the maximum number of top-level bookmarks
+// of front matter skipped to find the bookmark of the title.
+const maxOutlineEntries = 5
+
+// outlineOfDoc returns the title of the outline of doc, the bookmarks,
+// or the empty string if doc has no outline or the outline has no title.
+// This is the title of the outline root, set by a few producers, or the
+// first top-level bookmark, after the bookmarks of covers, unless it is
+// a section, like Introduction.
+func outlineOfDoc(doc *pdf.Reader) string {
+	outlines := doc.Trailer().Key("Root").Key("Outlines")
+	if tl := metaText(outlines.Key("Title").Text()); tl != "" {
+		return tl
+	}
+	item := outlines.Key("First")
+	for i := 0; i < maxOutlineEntries && !item.IsNull(); i++ {
+		tl := metaText(item.Key("Title").Text())
+		n := normalized(tl)
+		switch {
+		case slices.Contains(frontMatter, n):
+			item = item.Key("Next")
+			continue
+		case n == "" || slices.Contains(sectionHeadings, n) || sectionNumber.MatchString(tl):
+			return ""
+		}
+		return tl
+	}
+	return ""
+}
+
+// closeText returns true if a and b have the same text, see sameText,
+// or if one of them starts with the other at a word, like a short title
+// of a bookmark and the title with a subtitle of the page.
+func closeText(a, b string) bool {
+	na, nb := normalized(a), normalized(b)
+	if len(na) > len(nb) {
+		na, nb = nb, na
+	}
+	if na == "" || len(strings.Fields(na)) < 2 {
+		return na != "" && na == nb
+	}
+	return na == nb || strings.HasPrefix(nb, na+" ")
+}
+
+// agrees returns true if the text s of a page phrase is the text meta
+// of a metadata title of source. Bookmarks are often the title shortened
+// or with its subtitle so they need only be close to it, see closeText.
+func agrees(s, meta, source string) bool {
+	if source == SourceOutline {
+		return closeText(s, meta)
+	}
+	return sameText(s, meta)
+}
@@ -0,0 +1,385 @@
+package title
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"rsc.io/pdf"
+)
+
+// phrase represents a list of words that probably form a single phrase.
+// Phrases are defined loosely by checking letter font properties.
+type phrase struct {
+	font          string
+	fontSize      float64
+	spacing       float64
+	y             float64
+	sizeTolerance float64
+	minx          float64
+	maxx          float64
+	miny          float64
+	maxy          float64
+	box           pdf.Rect
+	repeated      bool
+	orient        orientation
+	rotated       bool
+	page          int
+	source        string
+	prevx         float64
+	prevy         float64
+	length        int
+	runs          int
+	steps         int
+	slants        int
+	lastx         float64
+	maxLen        int
+	trunc         int
+	gaps          []float64
+	baseline      float64
+	marks         [][2]int
+	b             strings.Builder
+}
+
+// newPhrases returns a new phrase starting with t.
+// spacingCoefficient multipied by font size determines if
+// two consecutive letters are in the same word. If maxLen
+// is positive, text after maxLen bytes is dropped.
+func newPhrase(t pdf.Text, spacingCoefficient, sizeTolerance float64, maxLen int) *phrase {
+	p := &phrase{
+		font:          t.Font,
+		fontSize:      t.FontSize,
+		spacing:       spacingCoefficient * t.FontSize,
+		sizeTolerance: sizeTolerance,
+		y:             t.Y,
+		minx:          t.X,
+		maxx:          t.X + t.W,
+		miny:          t.Y,
+		maxy:          t.Y,
+		maxLen:        maxLen,
+	}
+	p.write(written(t.S))
+	p.runs = 1
+	p.baseline = t.Y
+	p.lastx = t.X
+	p.prevx = t.X + t.W
+	p.prevy = t.Y
+	return p
+}
+
+// tryAppend tries to add t to the phrase and returns true if successful.
+func (p *phrase) tryAppend(t pdf.Text) bool {
+	// after some tests, it seems that if we are a bit loose with
+	// font names and sizes we can do better. Presentation slides
+	// use many fonts and both upper and lower case letters.
+	// Technical articles use standard fonts so names do not matter
+	fontFits := true
+	fontSizeFits := math.Abs(t.FontSize-p.fontSize) < p.sizeTolerance
+	canAppend := fontSizeFits && fontFits
+	if !canAppend {
+		return false
+	}
+	p.runs++
+	if p.maxLen > 0 && p.length >= p.maxLen {
+		return true
+	}
+
+	// combining marks are drawn over the previous letter, often
+	// raised, so they neither start a word nor move the baseline.
+	if r, _ := utf8.DecodeRuneInString(t.S); unicode.Is(unicode.M, r) {
+		p.write(written(t.S))
+		p.maxx = max(p.maxx, t.X+t.W)
+		return true
+	}
+
+	// steps to the right that also move up or down are
+	// slanted, like the letters of diagonal watermarks.
+	if dx := t.X - p.lastx; dx > 0 {
+		p.steps++
+		if dy := math.Abs(t.Y - p.prevy); dy > 0.2*dx && dy < 5*dx {
+			p.slants++
+		}
+	}
+
+	// do not add the separator at the beginning
+	newLine := false
+	if p.length > 0 {
+		last, _ := utf8.DecodeLastRuneInString(p.b.String())
+		first, _ := utf8.DecodeRuneInString(t.S)
+		joined := p.prevy-t.Y <= p.fontSize/2 && joinsIdeographs(last, first, t.X-p.prevx, p.fontSize)
+		if (t.Y < p.prevy || t.X-p.prevx >= p.spacing) && !joined {
+			// new lines are kept for repairing hyphenation.
+			sep := " "
+			if p.prevy-t.Y > p.fontSize/2 {
+				sep, newLine = "\n", true
+			} else {
+				p.gaps = append(p.gaps, t.X-p.prevx)
+			}
+			p.b.WriteString(sep)
+			p.length++
+		}
+	}
+	// marks are tracked by the offsets of the written text.
+	s := written(t.S)
+	p.trackMarks(t, s, newLine)
+	p.write(s)
+	p.lastx = t.X
+	p.prevx = t.X + t.W
+	p.prevy = t.Y
+	p.minx = min(p.minx, t.X)
+	p.maxx = max(p.maxx, t.X+t.W)
+	p.miny = min(p.miny, t.Y)
+	p.maxy = max(p.maxy, t.Y)
+	return true
+}
+
+// written returns the text s of a text run as it is written to
+// phrases, with non printable characters replaced and ligatures expanded.
+func written(s string) string {
+	return ligatures.Replace(printable(s))
+}
+
+// write appends the written text s of a text run, see written, to the phrase.
+func (p *phrase) write(s string) {
+	p.b.WriteString(s)
+	p.length += len(s)
+}
+
+// continues returns true if q is the next line of p, like the lines
+// of a wrapped title: the same font and size, just below p
+// and overlapping it horizontally.
+func (p *phrase) continues(q *phrase) bool {
+	if p.orient != q.orient || p.font != q.font || math.Abs(p.fontSize-q.fontSize) >= 0.5 {
+		return false
+	}
+	gap := p.prevy - q.y
+	if gap <= 0 || gap > 1.6*p.fontSize {
+		return false
+	}
+	return q.minx < p.maxx && q.maxx > p.minx
+}
+
+// diagonal returns true if most letters of p are on a slanted
+// baseline, like diagonal watermarks.
+func (p *phrase) diagonal() bool {
+	return p.steps >= 3 && 2*p.slants > p.steps
+}
+
+// merge appends the text of q, the next line of p, to p.
+func (p *phrase) merge(q *phrase) {
+	if p.maxLen > 0 && p.length >= p.maxLen {
+		p.runs += q.runs
+		return
+	}
+	p.b.WriteString("\n")
+	p.b.WriteString(q.b.String())
+	p.length += 1 + q.length
+	p.runs += q.runs
+	p.steps += q.steps
+	p.slants += q.slants
+	p.lastx = q.lastx
+	p.prevx = q.prevx
+	p.prevy = q.prevy
+	p.minx = min(p.minx, q.minx)
+	p.maxx = max(p.maxx, q.maxx)
+	p.miny = min(p.miny, q.miny)
+	p.maxy = max(p.maxy, q.maxy)
+}
+
+// mergeLines merges the phrases that continue a previous phrase
+// on the next line into it. Phrases are split when the lines of
+// a title are interrupted by text in another font size, like
+// footnote marks, so short phrases between the lines are skipped.
+func mergeLines(phrases []*phrase) []*phrase {
+	var merged []*phrase
+	for _, q := range phrases {
+		joined := false
+		for i := len(merged) - 1; i >= 0; i-- {
+			if merged[i].continues(q) {
+				merged[i].merge(q)
+				joined = true
+				break
+			}
+			if len(merged[i].text()) > 3 {
+				break
+			}
+		}
+		if !joined {
+			merged = append(merged, q)
+		}
+	}
+	return merged
+}
+
+// String returns the phrase as a single string.
+func (p *phrase) String() string {
+	// trim for the cases it misses the title and
+	// returns the document full text
+	return truncate(p.text(), p.trunc)
+}
+
+// truncate returns s cut to at most n bytes, preferably at a word
+// boundary. If n is not positive, s is returned unchanged.
+func truncate(s string, n int) string {
+	if n <= 0 || len(s) <= n {
+		return s
+	}
+	cut := n
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	if s[cut] != ' ' {
+		if i := strings.LastIndexByte(s[:cut], ' '); i > 0 {
+			cut = i
+		}
+	}
+	return strings.TrimRight(s[:cut], " ")
+}
+
+// bbox returns the bounding box of p as min x, min y, max x and max y.
+// The maximum y is the last baseline raised by the font size.
+func (p *phrase) bbox() [4]float64 {
+	return [4]float64{p.minx, p.miny, p.maxx, p.maxy + p.fontSize}
+}
+
+// text returns the whole text of p with spaces collapsed.
+func (p *phrase) text() string {
+	return strings.Join(strings.Fields(p.b.String()), " ")
+}
+
+// position returns the position of p on its page.
+func (p *phrase) position() *Position {
+	width := p.box.Max.X - p.box.Min.X
+	height := p.box.Max.Y - p.box.Min.Y
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	yf := (p.box.Max.Y - p.y) / height
+	mid := p.box.Min.X + width/2
+	return &Position{
+		YFraction: math.Round(min(1, max(0, yf))*1000) / 1000,
+		Centered:  math.Abs((p.minx+p.maxx)/2-mid) < 0.05*width,
+	}
+}
+
+// ligatures expands the typographic ligatures of fonts to their letters
+// so that titles match searches and dictionary words.
+var ligatures = strings.NewReplacer(
+	"\uFB00", "ff",
+	"\uFB01", "fi",
+	"\uFB02", "fl",
+	"\uFB03", "ffi",
+	"\uFB04", "ffl",
+	"\uFB05", "st",
+	"\uFB06", "st",
+)
+
+// printable returns a copy of s where all non printable characters
+// are replaced by a space. Combining marks are graphic so
+// decomposed accented letters survive.
+func printable(s string) string {
+	const space = rune(32)
+
+	runes := make([]rune, 0)
+	for {
+		r, siz := utf8.DecodeRuneInString(s)
+		if siz == 0 {
+			break
+		}
+		if r == utf8.RuneError {
+			runes = append(runes, space)
+		} else if unicode.IsGraphic(r) {
+			runes = append(runes, r)
+		} else {
+			runes = append(runes, space)
+		}
+		s = s[siz:]
+	}
+	return string(runes)
+}
+
+// minSpacedLetters is the minimum number of single letters
+// separated by spaces of letter-spaced text.
+const minSpacedLetters = 4
+
+// joinLetters joins the letters of p if p is letter-spaced, like the
+// titles typeset with heavy tracking that come out as T I T L E, since
+// every letter is farther than the spacing from the one before. The
+// words are separated by the gaps wider than the gaps of the letters,
+// the lower quartile of the gaps, by more than the spacing.
+func (p *phrase) joinLetters() {
+	s := p.b.String()
+	fields := strings.Fields(s)
+	single := 0
+	for _, f := range fields {
+		if utf8.RuneCountInString(f) == 1 {
+			single++
+		}
+	}
+	if single < minSpacedLetters || 4*single < 3*len(fields) || strings.Count(s, " ") != len(p.gaps) {
+		return
+	}
+	gaps := slices.Sorted(slices.Values(p.gaps))
+	wordGap := gaps[len(gaps)/4] + p.spacing
+
+	var b strings.Builder
+	i := 0
+	for _, r := range s {
+		if r == ' ' {
+			if p.gaps[i] >= wordGap {
+				b.WriteRune(r)
+			}
+			i++
+			continue
+		}
+		b.WriteRune(r)
+	}
+	p.b.Reset()
+	p.b.WriteString(b.String())
+	p.length = b.Len()
+}
+
+// footnoteMarks are the superscripts that mark the footnotes
+// of titles, like the acknowledgments of grants.
+const footnoteMarks = "0123456789*∗†‡§¶#⋆"
+
+// trackMarks records the superscript footnote marks, see footnoteMarks,
+// at the end of the lines of p, before t, written as s, see written,
+// is written. Superscripts are raised above the baseline and not larger
+// than the font of p and those followed by more text in the same line,
+// like exponents, are not marks.
+func (p *phrase) trackMarks(t pdf.Text, s string, newLine bool) {
+	r, _ := utf8.DecodeRuneInString(s)
+	raised := t.Y-p.baseline > 0.2*p.fontSize && t.FontSize <= p.fontSize
+	if !newLine && raised && strings.ContainsRune(footnoteMarks, r) {
+		if n := len(p.marks); n > 0 && p.marks[n-1][1] == p.b.Len() {
+			p.marks[n-1][1] += len(s)
+		} else {
+			p.marks = append(p.marks, [2]int{p.b.Len(), p.b.Len() + len(s)})
+		}
+		return
+	}
+	if n := len(p.marks); n > 0 && !newLine && p.marks[n-1][1] >= p.b.Len()-1 {
+		p.marks = p.marks[:n-1]
+	}
+	p.baseline = t.Y
+}
+
+// stripMarks removes the footnote marks at the end
+// of the lines of p, see trackMarks.
+func (p *phrase) stripMarks() {
+	if len(p.marks) == 0 {
+		return
+	}
+	s := p.b.String()
+	for i := len(p.marks) - 1; i >= 0; i-- {
+		m := p.marks[i]
+		s = s[:m[0]] + s[m[1]:]
+	}
+	p.b.Reset()
+	p.b.WriteString(s)
+	p.length = len(s)
+	p.marks = nil
+}
@@ -0,0 +1,58 @@
+package title
+
+import (
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+	"rsc.io/pdf"
+)
+
+func TestPrintableKeepsMarks(t *testing.T) {
+	s := "re\u0301sume\u0301"
+	if got := printable(s); got != s {
+		t.Errorf("printable(%q) = %q, want %q", s, got, s)
+	}
+}
+
+func TestDecomposedAccents(t *testing.T) {
+	// "Résumé Writing" drawn with the acute accents as separate
+	// runs over the previous letters, like fonts without
+	// precomposed letters do.
+	const size, w = 12.0, 6.0
+	var runs []pdf.Text
+	x := 72.0
+	for _, s := range []string{"R", "e", "\u0301", "s", "u", "m", "e", "\u0301", " ", "W", "r", "i", "t", "i", "n", "g"} {
+		switch s {
+		case "\u0301":
+			// the mark is over the letter before it.
+			runs = append(runs, pdf.Text{Font: "F1", FontSize: size, X: x - w, Y: 702, W: 0, S: s})
+		case " ":
+			x += w
+		default:
+			runs = append(runs, pdf.Text{Font: "F1", FontSize: size, X: x, Y: 700, W: w, S: s})
+			x += w
+		}
+	}
+	p := newPhrase(runs[0], 0.16, 4, 0)
+	for _, r := range runs[1:] {
+		if !p.tryAppend(r) {
+			t.Fatalf("tryAppend(%q) = false", r.S)
+		}
+	}
+	if got, want := norm.NFC.String(p.String()), "Résumé Writing"; got != want {
+		t.Errorf("phrase = %q, want %q", got, want)
+	}
+}
+
+func TestStripMarksWritten(t *testing.T) {
+	// a footnote mark whose run has a zero width space, written
+	// as a single space, shorter than the run itself.
+	p := newPhrase(pdf.Text{Font: "F1", FontSize: 12, X: 72, Y: 700, W: 60, S: "Title"}, 0.16, 4, 0)
+	if !p.tryAppend(pdf.Text{Font: "F2", FontSize: 9, X: 132, Y: 706, W: 4, S: "1\u200b"}) {
+		t.Fatal("tryAppend(mark) = false")
+	}
+	p.stripMarks()
+	if got, want := p.String(), "Title"; got != want {
+		t.Errorf("phrase = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,45 @@
+package title
+
+import (
+	"rsc.io/pdf"
+)
+
+// Phrase is text of a page assembled from consecutive glyphs
+// of the same font and size on the same line.
+type Phrase struct {
+	// Page is the number of the page of the phrase, starting at 1.
+	Page int `json:"page"`
+
+	// Text is the text of the phrase with spaces collapsed.
+	Text string `json:"text"`
+
+	Font     string  `json:"font"`
+	FontSize float64 `json:"font_size"`
+
+	// BBox is the bounding box of the phrase, in pdf units,
+	// as min x, min y, max x and max y.
+	BBox [4]float64 `json:"bbox"`
+}
+
+// Phrases returns the phrases of all pages of doc in reading order.
+// Words are assembled with the spacing coefficient of e.
+func (e *Extractor) Phrases(doc *pdf.Reader) (phrases []Phrase, rerr error) {
+	defer recoverReader(&rerr)
+
+	for i := 1; i <= e.numPage(doc); i++ {
+		page := doc.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		for _, p := range e.phrasesOfPage(page) {
+			phrases = append(phrases, Phrase{
+				Page:     i,
+				Text:     p.text(),
+				Font:     p.font,
+				FontSize: p.fontSize,
+				BBox:     p.bbox(),
+			})
+		}
+	}
+	return
+}
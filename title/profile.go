@@ -0,0 +1,120 @@
+package title
+
+import (
+	"rsc.io/pdf"
+)
+
+// Profiles of documents, see WithProfile.
+const (
+	// ProfilePaper is for articles and papers, the defaults.
+	ProfilePaper = "paper"
+
+	// ProfileSlides is for presentations. Slides use many fonts and
+	// sizes in the same line and short titles with few dictionary words.
+	ProfileSlides = "slides"
+
+	// ProfileBook is for books. Their title pages often follow
+	// covers, half titles and blank pages.
+	ProfileBook = "book"
+
+	// ProfileReport is for reports and theses, often
+	// with a cover before the title page.
+	ProfileReport = "report"
+
+	// ProfileAuto selects the profile of each document
+	// by its pages, see detectProfile.
+	ProfileAuto = "auto"
+)
+
+// thresholds are the thresholds of the heuristics set by a profile.
+type thresholds struct {
+	// spacing is the spacing coefficient of WithSpacing.
+	spacing float64
+
+	// sizeTolerance is the largest difference of font sizes
+	// of the text runs of a phrase.
+	sizeTolerance float64
+
+	// pages is the number of pages of WithPages.
+	pages int
+
+	// dictThreshold is the threshold of WithDictThreshold.
+	dictThreshold float64
+}
+
+// profiles are the thresholds of the profiles.
+var profiles = map[string]thresholds{
+	ProfilePaper:  {spacing: 0.16, sizeTolerance: 4, pages: 1, dictThreshold: 0.2},
+	ProfileSlides: {spacing: 0.2, sizeTolerance: 8, pages: 1, dictThreshold: 0.1},
+	ProfileBook:   {spacing: 0.16, sizeTolerance: 4, pages: 3, dictThreshold: 0.2},
+	ProfileReport: {spacing: 0.16, sizeTolerance: 4, pages: 2, dictThreshold: 0.2},
+}
+
+// Page counts of detectProfile.
+const (
+	minBookPages   = 150
+	minReportPages = 20
+)
+
+// IsProfile returns true if name is a profile, including ProfileAuto.
+func IsProfile(name string) bool {
+	_, ok := profiles[name]
+	return ok || name == ProfileAuto
+}
+
+// detectProfile returns the profile of doc: slides for landscape
+// pages, books and reports by their number of pages and else papers.
+func detectProfile(doc *pdf.Reader) string {
+	if n := doc.NumPage(); n > 0 {
+		box := pageBox(doc.Page(1))
+		if box.Max.X-box.Min.X > 1.1*(box.Max.Y-box.Min.Y) {
+			return ProfileSlides
+		}
+	}
+	switch n := doc.NumPage(); {
+	case n >= minBookPages:
+		return ProfileBook
+	case n >= minReportPages:
+		return ProfileReport
+	}
+	return ProfilePaper
+}
+
+// profiled returns e with the thresholds of the profile of
+// WithProfile for doc, except those set with their options,
+// or e if no profile is set.
+func (e *Extractor) profiled(doc *pdf.Reader) *Extractor {
+	name := e.profile
+	if name == ProfileAuto {
+		name = detectProfile(doc)
+	}
+	t, ok := profiles[name]
+	if !ok {
+		return e
+	}
+	pe := *e
+	pe.profile = name
+	pe.sizeTolerance = t.sizeTolerance
+	if !e.fixed.spacing {
+		pe.spacing = t.spacing
+	}
+	if !e.fixed.pages {
+		pe.pages = t.pages
+	}
+	if !e.fixed.dictThreshold {
+		pe.wordsInDictPercent = t.dictThreshold
+	}
+	e.logger.Debug("profile", "profile", name)
+	return &pe
+}
+
+// profiledDoc is like profiled for the document of docgen.
+func (e *Extractor) profiledDoc(docgen func() (*pdf.Reader, error)) (pe *Extractor, rerr error) {
+	defer recoverReader(&rerr)
+
+	doc, err := docgen()
+	if err != nil {
+		return nil, readerError(err)
+	}
+	return e.profiled(doc), nil
+}
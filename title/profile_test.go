@@ -0,0 +1,15 @@
+package title
+
+import "testing"
+
+func TestProfiledKeepsOptions(t *testing.T) {
+	e := New(WithProfile(ProfileSlides), WithSpacing(0.3), WithPages(2))
+	pe := e.profiled(nil)
+	if pe.spacing != 0.3 || pe.pages != 2 {
+		t.Errorf("spacing, pages = %v, %v, want 0.3, 2 of the options", pe.spacing, pe.pages)
+	}
+	slides := profiles[ProfileSlides]
+	if pe.wordsInDictPercent != slides.dictThreshold || pe.sizeTolerance != slides.sizeTolerance {
+		t.Errorf("dict threshold, size tolerance = %v, %v, want %v, %v of the profile", pe.wordsInDictPercent, pe.sizeTolerance, slides.dictThreshold, slides.sizeTolerance)
+	}
+}
@@ -0,0 +1,189 @@
+package title
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"rsc.io/pdf"
+)
+
+// orientation is the reading direction of text on a page.
+type orientation int
+
+const (
+	// upright text reads left to right.
+	upright orientation = iota
+
+	// rotatedUp text is rotated 90° counterclockwise and reads bottom to top.
+	rotatedUp
+
+	// upsideDown text is rotated 180° and reads right to left.
+	upsideDown
+
+	// rotatedDown text is rotated 90° clockwise and reads top to bottom.
+	rotatedDown
+)
+
+// point returns x, y in the coordinates where text of
+// orientation o is upright.
+func (o orientation) point(x, y float64) (float64, float64) {
+	switch o {
+	case rotatedUp:
+		return y, -x
+	case upsideDown:
+		return -x, -y
+	case rotatedDown:
+		return -y, x
+	}
+	return x, y
+}
+
+// rect returns r in the coordinates where text of
+// orientation o is upright.
+func (o orientation) rect(r pdf.Rect) pdf.Rect {
+	x0, y0 := o.point(r.Min.X, r.Min.Y)
+	x1, y1 := o.point(r.Max.X, r.Max.Y)
+	return pdf.Rect{
+		Min: pdf.Point{X: min(x0, x1), Y: min(y0, y1)},
+		Max: pdf.Point{X: max(x0, x1), Y: max(y0, y1)},
+	}
+}
+
+// orientText returns the text runs of page turned upright, with their
+// orientations, and the orientation of most runs. The reader reports
+// the font size of text rotated 90° as 0, and as negative for text
+// rotated 180°, and the positions of the runs in page coordinates, so
+// the lines of rotated text look like columns of letters to tryAppend.
+// The font sizes of text rotated 90° are estimated from the advances
+// of the letters and the widths of their fonts.
+func orientText(page pdf.Page, text []pdf.Text) ([]pdf.Text, []orientation, orientation) {
+	orients := make([]orientation, len(text))
+	for i := 0; i < len(text); {
+		t := text[i]
+		switch {
+		case t.FontSize < 0:
+			orients[i] = upsideDown
+			i++
+		case t.FontSize < 0.5:
+			j := i + 1
+			for j < len(text) && text[j].FontSize >= 0 && text[j].FontSize < 0.5 {
+				j++
+			}
+			o := rotatedUp
+			var dy float64
+			for k := i + 1; k < j; k++ {
+				if math.Abs(text[k].Y-text[k-1].Y) > math.Abs(text[k].X-text[k-1].X) {
+					dy += text[k].Y - text[k-1].Y
+				}
+			}
+			if dy < 0 {
+				o = rotatedDown
+			}
+			for k := i; k < j; k++ {
+				orients[k] = o
+			}
+			i = j
+		default:
+			i++
+		}
+	}
+
+	counts := make(map[orientation]int)
+	for _, o := range orients {
+		counts[o]++
+	}
+	major := upright
+	for _, o := range []orientation{rotatedUp, upsideDown, rotatedDown} {
+		if counts[o] > counts[major] {
+			major = o
+		}
+	}
+	if counts[upright] == len(text) {
+		return text, orients, major
+	}
+
+	widths := fontWidths(page)
+	oriented := slices.Clone(text)
+	for i := 0; i < len(text); {
+		o := orients[i]
+		if o == upright {
+			i++
+			continue
+		}
+		if o == upsideDown {
+			t := &oriented[i]
+			t.X, t.Y = o.point(t.X, t.Y)
+			t.FontSize, t.W = -t.FontSize, -t.W
+			i++
+			continue
+		}
+
+		// a line of text rotated 90° ends when the letters
+		// stop advancing along it.
+		j := i + 1
+		for j < len(text) && orients[j] == o {
+			x0, y0 := o.point(text[j-1].X, text[j-1].Y)
+			x1, y1 := o.point(text[j].X, text[j].Y)
+			if x1 <= x0 || math.Abs(y1-y0) > x1-x0 {
+				break
+			}
+			j++
+		}
+		var sizes []float64
+		for k := i; k+1 < j; k++ {
+			x0, _ := o.point(text[k].X, text[k].Y)
+			x1, _ := o.point(text[k+1].X, text[k+1].Y)
+			sizes = append(sizes, (x1-x0)*1000/widths.of(text[k]))
+		}
+		// the median ignores the advances over the spaces,
+		// the reader drops them.
+		size := 12.0
+		if len(sizes) > 0 {
+			slices.Sort(sizes)
+			size = sizes[len(sizes)/2]
+		}
+		for k := i; k < j; k++ {
+			t := &oriented[k]
+			t.X, t.Y = o.point(t.X, t.Y)
+			t.FontSize = size
+			t.W = widths.of(text[k]) / 1000 * size
+		}
+		i = j
+	}
+	return oriented, orients, major
+}
+
+// widthTable are the widths, in thousands of the font size, of the
+// letters of the fonts of a page by the base names of the fonts.
+type widthTable map[string]pdf.Font
+
+// fontWidths returns the widths of the fonts of page.
+func fontWidths(page pdf.Page) widthTable {
+	widths := make(widthTable)
+	for _, name := range page.Fonts() {
+		f := page.Font(name)
+		base := f.BaseFont()
+		if i := strings.Index(base, "+"); i >= 0 {
+			base = base[i+1:]
+		}
+		widths[base] = f
+	}
+	return widths
+}
+
+// of returns the width of the letter of t, or the average width
+// of letters if the font or the letter is unknown. The code of a
+// letter is assumed to be the letter, right for ASCII letters
+// of most simple fonts.
+func (w widthTable) of(t pdf.Text) float64 {
+	if f, ok := w[t.Font]; ok {
+		if r, _ := utf8.DecodeRuneInString(t.S); r < 256 {
+			if width := f.Width(int(r)); width > 0 {
+				return width
+			}
+		}
+	}
+	return 500
+}
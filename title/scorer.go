@@ -0,0 +1,187 @@
+package title
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A Scorer ranks title candidates. Rank receives the phrases of
+// the title page as candidates in reading order and returns them,
+// or a subset of them, from the most to the least likely title.
+// Rank must keep the Index of the candidates.
+type Scorer interface {
+	Rank(cands []Candidate) []Candidate
+}
+
+// ScorerFunc adapts a function to a Scorer.
+type ScorerFunc func(cands []Candidate) []Candidate
+
+// Rank calls f(cands).
+func (f ScorerFunc) Rank(cands []Candidate) []Candidate {
+	return f(cands)
+}
+
+// ByScore is a Scorer that ranks candidates by decreasing Score.
+var ByScore Scorer = ScorerFunc(func(cands []Candidate) []Candidate {
+	slices.SortStableFunc(cands, func(a, b Candidate) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return cands
+})
+
+// confidence returns the confidence, from 0 to 1, that cands[i] is the
+// title. It is the score of the candidate reduced by up to 40% as the
+// margin of its font size over the runner-up, the largest font of the
+// other phrases of the page, falls under a quarter of its font size.
+func confidence(cands []Candidate, i int) float64 {
+	c := cands[i]
+	if c.Source != SourcePage || c.FontSize <= 0 {
+		return c.Score
+	}
+	var runnerUp float64
+	for j, o := range cands {
+		if j != i && o.Source == SourcePage {
+			runnerUp = max(runnerUp, o.FontSize)
+		}
+	}
+	margin := min(1, max(0, (c.FontSize-runnerUp)/(0.25*c.FontSize)))
+	return math.Round(c.Score*(0.6+0.4*margin)*1000) / 1000
+}
+
+// Weights are the weights of the features of a candidate in its score.
+// The score is the weighted mean of the features, each from 0 to 1.
+type Weights struct {
+	// FontSize weighs the font size relative to the largest font of the page.
+	FontSize float64
+
+	// Top weighs the vertical position. It is 1 in the top third
+	// of the page and falls to 0 at the bottom.
+	Top float64
+
+	// Centered weighs horizontal centering.
+	Centered float64
+
+	// Bold weighs bold fonts. Titles are often only slightly
+	// larger than headings but bold.
+	Bold float64
+
+	// Dict weighs the ratio of dictionary words.
+	Dict float64
+
+	// Length weighs the number of words. It is 1 for 3 to 20 words,
+	// less for shorter phrases, like initials and logos, and falls
+	// to 0 for paragraphs of 50 words.
+	Length float64
+}
+
+// DefaultWeights are the weights of the score of candidates
+// unless set with WithWeights.
+var DefaultWeights = Weights{FontSize: 0.45, Top: 0.15, Centered: 0.1, Bold: 0.05, Dict: 0.15, Length: 0.1}
+
+// weightNames are the names of the weights in their text form.
+var weightNames = []string{"font", "top", "center", "bold", "dict", "length"}
+
+// fields returns pointers to the weights of w in the order of weightNames.
+func (w *Weights) fields() []*float64 {
+	return []*float64{&w.FontSize, &w.Top, &w.Centered, &w.Bold, &w.Dict, &w.Length}
+}
+
+// MarshalText returns w as a comma separated list of name=value pairs,
+// like font=0.45,top=0.15,center=0.1,bold=0.05,dict=0.15,length=0.1.
+func (w Weights) MarshalText() ([]byte, error) {
+	var parts []string
+	for i, f := range w.fields() {
+		parts = append(parts, weightNames[i]+"="+strconv.FormatFloat(*f, 'g', -1, 64))
+	}
+	return []byte(strings.Join(parts, ",")), nil
+}
+
+// UnmarshalText sets the weights of a comma separated list of
+// name=value pairs, like the output of MarshalText. Weights
+// missing from the list keep their values.
+func (w *Weights) UnmarshalText(text []byte) error {
+	fields := w.fields()
+	for pair := range strings.SplitSeq(string(text), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return fmt.Errorf("weight %q is not name=value", pair)
+		}
+		i := slices.Index(weightNames, name)
+		if i < 0 {
+			return fmt.Errorf("unknown weight %q, want one of %s", name, strings.Join(weightNames, ", "))
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil || v < 0 {
+			return fmt.Errorf("weight %s: %q is not a non negative number", name, value)
+		}
+		*fields[i] = v
+	}
+	return nil
+}
+
+// score returns the weighted mean of the features of c.
+// largest is the largest font size of the page.
+func (w Weights) score(c Candidate, largest float64) float64 {
+	var total, s float64
+	for i, f := range features(c, largest) {
+		total += *w.fields()[i]
+		s += *w.fields()[i] * f
+	}
+	if total <= 0 || largest <= 0 {
+		return 0
+	}
+	return s / total
+}
+
+// features returns the features of c, each from 0 to 1, in the order
+// of weightNames. largest is the largest font size of the page.
+func features(c Candidate, largest float64) []float64 {
+	var size float64
+	if largest > 0 {
+		size = c.FontSize / largest
+	}
+	return []float64{
+		size,
+		topScore(c.YFraction),
+		boolScore(c.Centered),
+		boolScore(c.Bold),
+		c.DictRatio,
+		lengthScore(len(strings.Fields(c.Text))),
+	}
+}
+
+// boolScore returns 1 for true and 0 for false.
+func boolScore(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// topScore returns 1 for the top third of the page, yf is the
+// distance from the top as a fraction of the page height,
+// and falls linearly to 0 at the bottom.
+func topScore(yf float64) float64 {
+	if yf <= 1.0/3 {
+		return 1
+	}
+	return max(0, 1-(yf-1.0/3)*1.5)
+}
+
+// lengthScore returns 1 for titles of 3 to 20 words, less for
+// 1 or 2 words and falls linearly to 0 for 50 words.
+func lengthScore(words int) float64 {
+	switch {
+	case words <= 0:
+		return 0
+	case words < 3:
+		return 0.3 * float64(words)
+	case words <= 20:
+		return 1
+	}
+	return max(0, 1-float64(words-20)/30)
+}
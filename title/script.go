@@ -0,0 +1,133 @@
+package title
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// dictScripts are the scripts of the languages of the dictionaries.
+// Titles in the other scripts, like Cyrillic, Greek or Arabic, have
+// no embedded dictionary. They are checked with the dictionaries of
+// WithDictionary in their script, if any, see scriptDictionary, and
+// else only by the scripts of their words, see scriptRatio.
+var dictScripts = []*unicode.RangeTable{unicode.Latin}
+
+// scriptOf returns the script of the letter r or nil if it
+// is not a letter of the scripts of unicode.Scripts.
+func scriptOf(r rune) *unicode.RangeTable {
+	if r < 0x80 {
+		if unicode.IsLetter(r) {
+			return unicode.Latin
+		}
+		return nil
+	}
+	for _, table := range unicode.Scripts {
+		if unicode.Is(table, r) && table != unicode.Common && table != unicode.Inherited {
+			return table
+		}
+	}
+	return nil
+}
+
+// scriptRatio returns, for the text s mostly of letters of scripts
+// without a dictionary, the fraction of its words with the letters
+// of a single script, in place of the fraction of dictionary words,
+// and the number of its words and true. Words mixing scripts, like
+// Latin and Cyrillic letters that look the same, are the text of fonts
+// whose codes are not mapped to the right letters. This is not a check
+// of the words themselves: any text of words of a single script, even
+// a random one, passes. It returns false if s is mostly of letters of
+// the scripts of the dictionaries.
+func scriptRatio(s string) (float64, int, bool) {
+	letters, foreign := 0, 0
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if table := scriptOf(r); table != nil && !slices.Contains(dictScripts, table) {
+			foreign++
+		}
+	}
+	if foreign < 3 || 2*foreign < letters {
+		return 0, 0, false
+	}
+	words, single := 0, 0
+	for _, w := range wordsExtractor.FindAllString(s, -1) {
+		words++
+		var first *unicode.RangeTable
+		mixed := false
+		for _, r := range w {
+			table := scriptOf(r)
+			if table == nil {
+				continue
+			}
+			if first == nil {
+				first = table
+			} else if table != first {
+				mixed = true
+			}
+		}
+		if !mixed {
+			single++
+		}
+	}
+	if words == 0 {
+		return 0, 0, true
+	}
+	return float64(single) / float64(words), words, true
+}
+
+// mainScript returns the script of most of the letters of s,
+// or nil if s has no letters.
+func mainScript(s string) *unicode.RangeTable {
+	counts := make(map[*unicode.RangeTable]int)
+	var main *unicode.RangeTable
+	for _, r := range s {
+		if table := scriptOf(r); table != nil {
+			counts[table]++
+			if counts[table] > counts[main] {
+				main = table
+			}
+		}
+	}
+	return main
+}
+
+// scriptDictionary returns the code of the language of the dictionary
+// of WithDictionary in the main script of s, see mainScript, with the
+// most words of s, like a Russian word list for a Cyrillic title, or
+// the empty string if there is none.
+func (e *Extractor) scriptDictionary(s string) string {
+	script := mainScript(s)
+	best, bestRatio := "", -1.0
+	for code, words := range e.dicts {
+		for w := range words {
+			if mainScript(w) == script {
+				if ratio := e.languageRatio(s, code); ratio > bestRatio || ratio == bestRatio && code < best {
+					best, bestRatio = code, ratio
+				}
+			}
+			break
+		}
+	}
+	return best
+}
+
+// withoutAccents returns s with the combining marks of its letters
+// removed, like resume for résumé, for looking up words of
+// dictionaries without accents.
+func withoutAccents(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(s))
+}
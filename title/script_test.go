@@ -0,0 +1,56 @@
+package title
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestScriptRatio(t *testing.T) {
+	tests := []struct {
+		s    string
+		want float64
+		ok   bool
+	}{
+		{"Attention Is All You Need", 0, false},
+		// any words of a single script pass, scriptRatio does not
+		// validate the words themselves.
+		{"Привет мир", 1, true},
+		{"Жщфх бвгд", 1, true},
+		// Latin p, e and o among Cyrillic letters of broken fonts.
+		{"Пpивeт мир нoвый", 1.0 / 3, true},
+		{"Μηχανική μάθηση", 1, true},
+	}
+	for _, tt := range tests {
+		got, _, ok := scriptRatio(tt.s)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("scriptRatio(%q) = %.2f, %v, want %.2f, %v", tt.s, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDictCheckScriptDictionary(t *testing.T) {
+	ru := WithDictionary(language.Russian, "привет", "мир", "машинное", "обучение")
+	tests := []struct {
+		name string
+		opts []Option
+		s    string
+		want bool
+	}{
+		{"no dictionary", nil, "Привет мир", true},
+		{"not validated without dictionary", nil, "Жщфх бвгд", true},
+		{"mixed scripts", nil, "Пpивeт нoвый", false},
+		{"dictionary words", []Option{ru}, "Машинное обучение", true},
+		{"not dictionary words", []Option{ru}, "Жщфх бвгд", false},
+		{"dictionary of another script", []Option{WithDictionary(language.German, "haus")}, "Жщфх бвгд", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(tt.opts...)
+			if got := e.dictCheck(tt.s); got != tt.want {
+				ratio, n := e.dictRatio(tt.s)
+				t.Errorf("dictCheck(%q) = %v, want %v (ratio %.2f of %d words)", tt.s, got, tt.want, ratio, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package title
+
+import (
+	"math"
+
+	"rsc.io/pdf"
+)
+
+// shadowOffset is the largest offset, relative to the font size,
+// of a copy of a letter drawn as its shadow or its outline.
+const shadowOffset = 0.1
+
+// withoutShadows returns text without the copies of the letters drawn
+// again at almost the same position, like the shadows and the outlines
+// of the titles of slides and the letters of fake bold fonts, that
+// would double the letters or the phrases of titles. The first copy
+// of each letter is kept. Letters without widths, like the letters of
+// CID fonts, are all drawn at the start of their text and are kept.
+func withoutShadows(text []pdf.Text) []pdf.Text {
+	seen := make(map[string][]pdf.Text)
+	kept := text[:0:0]
+	for _, t := range text {
+		if t.W > 0 && isShadow(t, seen[t.S]) {
+			continue
+		}
+		seen[t.S] = append(seen[t.S], t)
+		kept = append(kept, t)
+	}
+	return kept
+}
+
+// isShadow returns true if t is a copy of one of the same letters
+// drawn before it, see withoutShadows.
+func isShadow(t pdf.Text, same []pdf.Text) bool {
+	d := shadowOffset * t.FontSize
+	for _, s := range same {
+		if math.Abs(s.FontSize-t.FontSize) < 0.5 && math.Abs(s.X-t.X) <= d && math.Abs(s.Y-t.Y) <= d {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package title
+
+import (
+	"strings"
+	"unicode"
+)
+
+// privateSymbols is the start of the block of the private use area
+// where symbolic TrueType fonts map their codes, like U+F041 for A.
+const privateSymbols = 0xF000
+
+// minUnshiftedRatio is the minimum ratio of dictionary
+// words of text recovered by shifting its letters.
+const minUnshiftedRatio = 0.5
+
+// maxUnshiftedOdd is the maximum fraction of odd words, see
+// isOddWord, of text recovered by shifting its letters.
+const maxUnshiftedOdd = 0.2
+
+// unshifted returns s recovered from the codes of a font whose codes
+// are not mapped to the right letters, and true if s is garbled text
+// and it was recovered. Subsetted fonts, often CID fonts, without usable
+// ToUnicode maps give the glyph indices of the subset, shifted by a
+// constant from the letters, like 7KH for The, and symbolic fonts give
+// the codes in the private use area. The shift that makes the most
+// dictionary words is the one of the font.
+func (e *Extractor) unshifted(s string) (string, bool) {
+	if !e.isGarbled(s) {
+		return s, false
+	}
+	if strings.ContainsFunc(s, isPrivateSymbol) {
+		s = strings.Map(func(r rune) rune {
+			if isPrivateSymbol(r) {
+				return r - privateSymbols
+			}
+			return r
+		}, s)
+		if !e.isGarbled(s) {
+			return s, true
+		}
+	}
+
+	best, bestRatio := "", 0.0
+	for shift := rune(-95); shift <= 95; shift++ {
+		if shift == 0 {
+			continue
+		}
+		t := shifted(s, shift)
+		if t == "" {
+			continue
+		}
+		if ratio, n := e.dictRatio(t); n >= 2 && ratio > bestRatio {
+			best, bestRatio = t, ratio
+		}
+	}
+	if bestRatio < minUnshiftedRatio || oddRatio(best) > maxUnshiftedOdd {
+		return s, false
+	}
+	return best, true
+}
+
+// shifted returns s with the printable ASCII characters, except
+// spaces, shifted by shift, or the empty string if a character
+// is shifted out of printable ASCII.
+func shifted(s string, shift rune) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			b.WriteRune(r)
+			continue
+		}
+		r += shift
+		if r <= ' ' || r > '~' {
+			return ""
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isPrivateSymbol returns true if r is the code of a letter
+// of a symbolic font in the private use area.
+func isPrivateSymbol(r rune) bool {
+	return r >= privateSymbols+' ' && r <= privateSymbols+0xFF
+}
+
+// unshift recovers the text of p if it is garbled, see unshifted.
+func (e *Extractor) unshift(p *phrase) {
+	s := p.b.String()
+	if t, ok := e.unshifted(s); ok {
+		e.logger.Debug("recovered garbled text", "text", s, "recovered", t)
+		p.b.Reset()
+		p.b.WriteString(t)
+	}
+}
@@ -0,0 +1,111 @@
+package title
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/caneroj1/stemmer"
+)
+
+// A lightStemmer is a light stemmer after the Snowball stemmers. It
+// removes the longest of the suffixes of its language in the R1 region
+// of words, the part after the first consonant that follows a vowel,
+// and then the longest of the residual suffixes, like the endings of
+// plurals. These are only the main suffix steps of the Snowball
+// algorithms, enough to match the inflections of words with the words
+// of word lists, not full Snowball stemmers.
+type lightStemmer struct {
+	// suffixes are the derivational and inflectional suffixes.
+	suffixes []string
+
+	// residual are the endings removed after the suffixes.
+	residual []string
+
+	// minR1 is the minimum number of letters before R1.
+	minR1 int
+
+	// final rewrites the stem, like the umlauts of German.
+	final *strings.Replacer
+}
+
+// stemmerVowels are the vowels of the languages of the stemmers.
+const stemmerVowels = "aeiouyäöüáéíóúàèìòùâêîôûãõœæëïÿ"
+
+// stemmers are the stemmers of the languages of the
+// word lists of langsFS by their code.
+var stemmers = map[string]lightStemmer{
+	"de": {
+		suffixes: []string{"ungen", "heiten", "keiten", "lichen", "ische", "ischen", "ung", "heit", "keit", "lich", "isch", "ern", "em", "er", "en", "es", "est", "st"},
+		residual: []string{"e", "s"},
+		minR1:    3,
+		final:    strings.NewReplacer("ä", "a", "ö", "o", "ü", "u", "ß", "ss"),
+	},
+	"fr": {
+		suffixes: []string{"issements", "issement", "atrices", "atrice", "ateurs", "ateur", "ations", "ation", "logies", "logie", "ements", "ement", "ances", "ance", "ences", "ence", "ismes", "isme", "istes", "iste", "ables", "able", "euses", "euse", "ités", "ité", "ives", "ive", "ifs", "if", "eux", "aux", "al"},
+		residual: []string{"es", "e", "é", "s", "x"},
+		minR1:    2,
+	},
+	"es": {
+		suffixes: []string{"amientos", "imientos", "amiento", "imiento", "aciones", "uciones", "adoras", "adores", "ancias", "logías", "encias", "amente", "idades", "ismos", "ables", "ibles", "istas", "adora", "ación", "ución", "ancia", "logía", "encia", "mente", "idad", "ismo", "able", "ible", "ista", "ador", "osos", "osas", "icos", "icas", "ivos", "ivas", "oso", "osa", "ico", "ica", "ivo", "iva"},
+		residual: []string{"es", "os", "as", "a", "o", "e", "á", "é", "í", "ó"},
+		minR1:    2,
+	},
+	"it": {
+		suffixes: []string{"azione", "azioni", "amento", "amenti", "imento", "imenti", "atore", "atori", "abile", "abili", "ibile", "ibili", "mente", "anza", "anze", "enza", "enze", "ista", "iste", "isti", "ismo", "ismi", "ità", "ico", "ici", "ica", "ice", "oso", "osi", "osa", "ose"},
+		residual: []string{"a", "e", "i", "o", "à", "è", "ì", "ò"},
+		minR1:    2,
+	},
+	"pt": {
+		suffixes: []string{"amentos", "imentos", "amento", "imento", "adoras", "adores", "logias", "ências", "amente", "idades", "ações", "adora", "ação", "logia", "ência", "mente", "idade", "ismos", "istas", "áveis", "íveis", "ismo", "ista", "ável", "ível", "osos", "osas", "icos", "icas", "ivos", "ivas", "oso", "osa", "ico", "ica", "ivo", "iva"},
+		residual: []string{"os", "as", "a", "o", "e", "á", "é", "ê", "í", "ó"},
+		minR1:    2,
+	},
+	"nl": {
+		suffixes: []string{"heden", "ingen", "heid", "lijk", "baar", "ing", "end", "ene", "en", "se", "s"},
+		residual: []string{"e"},
+		minR1:    3,
+		final:    strings.NewReplacer("kk", "k", "dd", "d", "tt", "t"),
+	},
+}
+
+// stem returns the stem of the word w in lower case.
+func (s lightStemmer) stem(w string) string {
+	r := []rune(w)
+	r1 := len(r)
+	for i := 1; i < len(r); i++ {
+		if !strings.ContainsRune(stemmerVowels, r[i]) && strings.ContainsRune(stemmerVowels, r[i-1]) {
+			r1 = i + 1
+			break
+		}
+	}
+	r1 = max(r1, s.minR1)
+	cut := func(suffixes []string) {
+		longest := 0
+		for _, suffix := range suffixes {
+			n := utf8.RuneCountInString(suffix)
+			if n > longest && strings.HasSuffix(string(r), suffix) && len(r)-n >= r1 {
+				longest = n
+			}
+		}
+		r = r[:len(r)-longest]
+	}
+	cut(s.suffixes)
+	cut(s.residual)
+	if s.final != nil {
+		return s.final.Replace(string(r))
+	}
+	return string(r)
+}
+
+// stemOf returns the stem of the word w with the stemmer of the
+// language code, or w if there is no stemmer for it. Words of
+// languages other than English must be in lower case.
+func stemOf(w, code string) string {
+	if code == english {
+		return stemmer.Stem(w)
+	}
+	if s, ok := stemmers[code]; ok {
+		return s.stem(w)
+	}
+	return w
+}
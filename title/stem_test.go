@@ -0,0 +1,54 @@
+package title
+
+import "testing"
+
+func TestStemOf(t *testing.T) {
+	tests := []struct {
+		code, w, want string
+	}{
+		{"de", "untersuchungen", "untersuch"},
+		{"de", "untersuchung", "untersuch"},
+		{"de", "logischen", "log"},
+		{"de", "logische", "log"},
+		{"de", "logisch", "log"},
+		{"de", "systemen", "system"},
+		{"de", "systeme", "system"},
+		{"de", "möglichkeiten", "moglich"},
+		{"de", "häuser", "haus"},
+		{"fr", "méthodes", "méthod"},
+		{"fr", "applications", "applic"},
+		{"fr", "nationaux", "nation"},
+		{"fr", "national", "nation"},
+		{"es", "sistemas", "sistem"},
+		{"es", "distribuidos", "distribuid"},
+		{"es", "aplicaciones", "aplic"},
+		{"es", "aplicación", "aplic"},
+		{"it", "sistemi", "sistem"},
+		{"it", "applicazioni", "applic"},
+		{"pt", "aplicações", "aplic"},
+		{"pt", "aplicação", "aplic"},
+		{"nl", "onderzoeken", "onderzoek"},
+		{"nl", "mogelijkheden", "mogelijk"},
+		{"nl", "toepassingen", "toepass"},
+		{"nl", "toepassing", "toepass"},
+		// short words, without R1, are kept.
+		{"de", "der", "der"},
+		{"fr", "les", "les"},
+		// no stemmer.
+		{"fi", "järjestelmät", "järjestelmät"},
+	}
+	for _, tt := range tests {
+		if got := stemOf(tt.w, tt.code); got != tt.want {
+			t.Errorf("stemOf(%q, %q) = %q, want %q", tt.w, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestStemmerSuffixOrder(t *testing.T) {
+	// the longest suffix is removed whatever
+	// the order of the suffixes of a stemmer.
+	s := lightStemmer{suffixes: []string{"e", "ische", "ischen", "en"}, minR1: 3}
+	if got := s.stem("logischen"); got != "log" {
+		t.Errorf("stem(logischen) = %q, want log", got)
+	}
+}
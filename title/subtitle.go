@@ -0,0 +1,46 @@
+package title
+
+import (
+	"strings"
+)
+
+// subtitleOf returns the subtitle of the title phrase tp of phrases, or
+// nil if it has none. The subtitle is the phrase with the largest font
+// just below the title, in a smaller font of the same family and weight.
+// Author lines, usually in a regular font under a bold title, are not.
+func (e *Extractor) subtitleOf(phrases []*phrase, tp *phrase) *phrase {
+	var sub *phrase
+	for _, p := range phrases {
+		if p == tp || p.source != "" || p.page != tp.page {
+			continue
+		}
+		gap := tp.prevy - p.y
+		switch {
+		case p.fontSize >= tp.fontSize || p.fontSize < 0.6*tp.fontSize:
+		case gap <= 0 || gap > 2.5*tp.fontSize:
+		case p.minx >= tp.maxx || p.maxx <= tp.minx:
+		case fontFamily(p.font) != fontFamily(tp.font) || isBold(p.font) != isBold(tp.font):
+		default:
+			if sub == nil || p.fontSize > sub.fontSize {
+				sub = p
+			}
+		}
+	}
+	if sub == nil {
+		return nil
+	}
+	if ratio, nwords := e.dictRatio(sub.String()); nwords < 2 || ratio == 0 {
+		e.logger.Debug("rejected subtitle", "text", sub.String(), "reason", "too few dictionary words")
+		return nil
+	}
+	return sub
+}
+
+// withSubtitle returns the title tl followed by its subtitle sub
+// separated by sep, or by a space if tl ends with a separator.
+func withSubtitle(tl, sub, sep string) string {
+	if strings.HasSuffix(tl, ":") || strings.HasSuffix(tl, "-") || strings.HasSuffix(tl, "—") {
+		sep = " "
+	}
+	return tl + sep + sub
+}
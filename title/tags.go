@@ -0,0 +1,247 @@
+package title
+
+import (
+	"slices"
+	"strings"
+
+	"rsc.io/pdf"
+)
+
+// Structure types, after the role map, of the title in tagged pdfs.
+const (
+	tagTitle = "Title"
+	tagH1    = "H1"
+)
+
+// maxTagDepth is the maximum number of ancestors of a structure
+// element followed to find its structure type.
+const maxTagDepth = 32
+
+// taggedTitle returns the text of page tagged as the title in the
+// structure tree of doc, the content of the Title element, or else
+// of the first H1 element, of the page, or the empty string if doc
+// is not tagged. H1 elements of sections, like Introduction, are
+// not titles.
+func taggedTitle(doc *pdf.Reader, page pdf.Page) string {
+	tree := doc.Trailer().Key("Root").Key("StructTreeRoot")
+	if tree.IsNull() {
+		return ""
+	}
+	parents := numberTreeValue(tree.Key("ParentTree"), page.V.Key("StructParents").Int64())
+	if parents.Kind() != pdf.Array {
+		return ""
+	}
+	roles := tree.Key("RoleMap")
+
+	var title, h1 strings.Builder
+	inH1, h1Done := false, false
+	for _, mc := range markedContentOf(page) {
+		tag := ""
+		if mc.mcid < parents.Len() {
+			tag = structType(parents.Index(mc.mcid), roles)
+		}
+		switch tag {
+		case tagTitle:
+			title.WriteString(mc.text)
+			title.WriteString(" ")
+		case tagH1:
+			if !h1Done {
+				h1.WriteString(mc.text)
+				h1.WriteString(" ")
+				inH1 = true
+			}
+		default:
+			// the first H1 ends at the next content.
+			h1Done = h1Done || inH1
+		}
+	}
+	if tl := metaText(title.String()); tl != "" {
+		return tl
+	}
+	tl := metaText(h1.String())
+	if n := normalized(tl); n == "" || slices.Contains(sectionHeadings, n) || sectionNumber.MatchString(tl) {
+		return ""
+	}
+	return tl
+}
+
+// structType returns the standard structure type of the structure
+// element elem or of its closest ancestor tagged Title or H1, mapped
+// with the role map roles, or the empty string if there is none.
+func structType(elem, roles pdf.Value) string {
+	for i := 0; i < maxTagDepth && elem.Kind() == pdf.Dict; i++ {
+		s := elem.Key("S").Name()
+		for j := 0; j < maxTagDepth; j++ {
+			m := roles.Key(s).Name()
+			if m == "" || m == s {
+				break
+			}
+			s = m
+		}
+		if s == tagTitle || s == tagH1 {
+			return s
+		}
+		elem = elem.Key("P")
+	}
+	return ""
+}
+
+// numberTreeValue returns the value of key n in the number tree node,
+// or a null value if the tree has no such key.
+func numberTreeValue(node pdf.Value, n int64) pdf.Value {
+	for depth := 0; depth < maxTagDepth && node.Kind() == pdf.Dict; depth++ {
+		nums := node.Key("Nums")
+		for i := 0; i+1 < nums.Len(); i += 2 {
+			if nums.Index(i).Int64() == n {
+				return nums.Index(i + 1)
+			}
+		}
+		kids := node.Key("Kids")
+		next := pdf.Value{}
+		for i := 0; i < kids.Len(); i++ {
+			kid := kids.Index(i)
+			limits := kid.Key("Limits")
+			if limits.Len() == 2 && (n < limits.Index(0).Int64() || n > limits.Index(1).Int64()) {
+				continue
+			}
+			next = kid
+			break
+		}
+		node = next
+	}
+	return pdf.Value{}
+}
+
+// markedContent is the text of a sequence of marked content of a page
+// with a marked content identifier, the key of its structure element.
+type markedContent struct {
+	mcid int
+	text string
+}
+
+// markedContentOf returns the texts of the marked content sequences
+// of page with identifiers, in the order of the content stream.
+// Text positioned on a new line is separated by a space.
+func markedContentOf(page pdf.Page) []markedContent {
+	var mcs []markedContent
+	var stack []int // the identifiers of the open sequences, -1 if none.
+	var b strings.Builder
+	var enc pdf.TextEncoding
+	current := func() int {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i] >= 0 {
+				return stack[i]
+			}
+		}
+		return -1
+	}
+	flush := func() {
+		if id := current(); id >= 0 && b.Len() > 0 {
+			mcs = append(mcs, markedContent{id, b.String()})
+		}
+		b.Reset()
+	}
+	show := func(s pdf.Value) {
+		if current() < 0 || enc == nil {
+			return
+		}
+		b.WriteString(enc.Decode(s.RawString()))
+	}
+	properties := page.Resources().Key("Properties")
+
+	interpret := func(strm pdf.Value) {
+		pdf.Interpret(strm, func(stk *pdf.Stack, op string) {
+			n := stk.Len()
+			args := make([]pdf.Value, n)
+			for i := n - 1; i >= 0; i-- {
+				args[i] = stk.Pop()
+			}
+			switch op {
+			case "BMC":
+				flush()
+				stack = append(stack, -1)
+			case "BDC":
+				flush()
+				id := -1
+				if len(args) == 2 {
+					props := args[1]
+					if props.Kind() == pdf.Name {
+						props = properties.Key(props.Name())
+					}
+					if mcid := props.Key("MCID"); mcid.Kind() == pdf.Integer {
+						id = int(mcid.Int64())
+					}
+				}
+				stack = append(stack, id)
+			case "EMC":
+				flush()
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+			case "Tf":
+				if len(args) == 2 {
+					enc = page.Font(args[0].Name()).Encoder()
+				}
+			case "T*":
+				b.WriteString(" ")
+			case "Td", "TD":
+				if len(args) == 2 && args[1].Float64() != 0 {
+					b.WriteString(" ")
+				}
+			case "Tj":
+				if len(args) == 1 {
+					show(args[0])
+				}
+			case "'", "\"":
+				b.WriteString(" ")
+				if len(args) > 0 {
+					show(args[len(args)-1])
+				}
+			case "TJ":
+				if len(args) != 1 {
+					break
+				}
+				for i := 0; i < args[0].Len(); i++ {
+					v := args[0].Index(i)
+					if v.Kind() == pdf.String {
+						show(v)
+					} else if v.Float64() < -250 {
+						b.WriteString(" ")
+					}
+				}
+			}
+		})
+	}
+	contents := page.V.Key("Contents")
+	if contents.Kind() == pdf.Array {
+		for i := 0; i < contents.Len(); i++ {
+			interpret(contents.Index(i))
+		}
+	} else {
+		interpret(contents)
+	}
+	return mcs
+}
+
+// taggedPhrase returns the phrase of the page with the text of the
+// title tagged in the structure tree, see taggedTitle, or the tagged
+// title itself if there is no such phrase, or nil if there is no
+// tagged title.
+func taggedPhrase(phrases []*phrase) *phrase {
+	i := slices.IndexFunc(phrases, func(p *phrase) bool {
+		return p.source == SourceTags
+	})
+	if i < 0 {
+		return nil
+	}
+	var tp *phrase
+	for _, p := range phrases {
+		if p.source == "" && sameText(p.String(), phrases[i].String()) && (tp == nil || p.fontSize > tp.fontSize) {
+			tp = p
+		}
+	}
+	if tp == nil {
+		return phrases[i]
+	}
+	return tp
+}
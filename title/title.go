@@ -0,0 +1,1146 @@
+// Package title extracts the titles of pdf documents.
+//
+// It works using heuristics on font sizes and names of the text
+// of the first page, nothing fancy like ML or CV. Compressed pdfs
+// the pdf reader cannot handle are transformed with ghostscript.
+package title
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"rsc.io/pdf"
+)
+
+// quotePairs are the opening and closing quotation marks
+// removed by WithStripQuotes.
+var quotePairs = []string{`""`, "''", "“”", "‘’", "„“", "„”", "‚‘", "«»", "»«", "‹›", "›‹", "「」", "『』", "《》", "〈〉"}
+
+// Extract tries to extract the title of the pdf file path
+// with the default configuration.
+func Extract(path string) (string, error) {
+	return New().Extract(path)
+}
+
+// ExtractContext is like Extract but stops when ctx is done.
+func ExtractContext(ctx context.Context, path string) (string, error) {
+	return New().ExtractContext(ctx, path)
+}
+
+// Extract tries to extract the title of the pdf file path.
+func (e *Extractor) Extract(path string) (string, error) {
+	return e.ExtractContext(context.Background(), path)
+}
+
+// ExtractContext is like Extract but stops when ctx is done.
+// The pdf reader checks ctx between pages and ghostscript
+// is killed if ctx is done before it completes.
+func (e *Extractor) ExtractContext(ctx context.Context, path string) (string, error) {
+	res, err := e.ExtractResult(ctx, path)
+	return res.Title, err
+}
+
+// Result is a title with the provenance of the guess.
+type Result struct {
+	Title string `json:"title"`
+
+	// Subtitle is the phrase just below the title in a smaller font
+	// of the same family and weight, if any. It is part of the title only
+	// with WithSubtitle.
+	Subtitle string `json:"subtitle,omitempty"`
+
+	// Page is the number of the page of the title, starting at 1.
+	// Font, FontSize, BBox and Position describe the text of the title.
+	// They are zero if the title does not come from the text of a page.
+	Page     int        `json:"page,omitempty"`
+	Font     string     `json:"font,omitempty"`
+	FontSize float64    `json:"font_size,omitempty"`
+	BBox     [4]float64 `json:"bbox"`
+	Position *Position  `json:"position,omitempty"`
+
+	// Confidence, from 0 to 1, is the score of the title as a
+	// candidate, see Ranked, reduced when the font of another
+	// phrase of the page is almost as large. Titles with low
+	// confidence are worth a manual review.
+	Confidence float64 `json:"confidence"`
+
+	// Pages is the number of pages of the pdf. Phrases is the number
+	// of phrases of the title page and TextRuns the number of text runs
+	// of the reader that make them.
+	Pages    int `json:"pages"`
+	Phrases  int `json:"phrases"`
+	TextRuns int `json:"text_runs"`
+
+	// Authors are the names of the author lines below the title.
+	// They are set only with WithAuthors.
+	Authors []string `json:"authors,omitempty"`
+
+	// Abstract is the paragraph after the Abstract heading
+	// of the first pages. It is set only with WithAbstract.
+	Abstract string `json:"abstract,omitempty"`
+
+	// DOI is the first DOI in the text of the title page.
+	// It is set only with WithIdentifiers.
+	DOI string `json:"doi,omitempty"`
+
+	// ArXiv is the first arXiv identifier, like 2104.12345v2, in the
+	// text of the title page. It is set only with WithIdentifiers.
+	ArXiv string `json:"arxiv,omitempty"`
+
+	// ISBNs are the ISBNs, without hyphens, with valid check digits
+	// in the first pages up to the two pages after the title page.
+	// They are set only with WithIdentifiers.
+	ISBNs []string `json:"isbns,omitempty"`
+
+	// Year is the likely publication year, from the copyright notice,
+	// the arXiv stamp or a header line of the title page, or 0 if it
+	// is unknown. It is set only with WithYear.
+	Year int `json:"year,omitempty"`
+
+	// Venue is the name of the journal or the conference of the
+	// running header or the banner of the title page, like IEEE
+	// Transactions on Software Engineering. Venues are never titles.
+	// It is set only with WithVenue.
+	Venue string `json:"venue,omitempty"`
+
+	// Keywords are the keywords of the Keywords or Index Terms line
+	// of the first pages. They are set only with WithKeywords.
+	Keywords []string `json:"keywords,omitempty"`
+
+	// Profile is the profile of the document, like paper or
+	// slides. It is set only with WithProfile.
+	Profile string `json:"profile,omitempty"`
+
+	// Author is the /Author of the document Info dictionary or the
+	// dc:creator entries of the XMP metadata, separated by commas.
+	// It is set only with WithInfo or WithXMP.
+	Author string `json:"author,omitempty"`
+
+	// Decoded is true if the pdf had to be transformed
+	// by the decoder, usually ghostscript.
+	Decoded bool `json:"decoded"`
+
+	// Elapsed is the duration of the extraction.
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// ExtractResult is like ExtractContext but returns the title
+// with the details of where it was found.
+func (e *Extractor) ExtractResult(ctx context.Context, path string) (res Result, err error) {
+	start := time.Now()
+	// scanDoc calls scan a second time only for the decoded pdf.
+	calls := 0
+	err = e.scanDoc(ctx, path, func(docgen func() (*pdf.Reader, error)) error {
+		calls++
+		var serr error
+		res, serr = e.titleOfDoc(ctx, docgen)
+		res.Decoded = calls > 1
+		return serr
+	})
+	res.Elapsed = time.Since(start)
+	e.logger.Debug("extracted title", "path", path, "title", res.Title, "decoded", res.Decoded, "elapsed", res.Elapsed, "err", err)
+	return
+}
+
+// Position is the position of a title on its page.
+type Position struct {
+	// YFraction is the distance of the title from the top
+	// of the page as a fraction of the page height.
+	YFraction float64 `json:"y_fraction"`
+
+	// Centered is true if the title is horizontally centered.
+	Centered bool `json:"centered"`
+}
+
+// ExtractWithPosition is like Extract but also returns the position
+// of the title on its page. The position is nil if the title
+// does not come from the text of the page.
+func (e *Extractor) ExtractWithPosition(path string) (string, *Position, error) {
+	res, err := e.ExtractResult(context.Background(), path)
+	return res.Title, res.Position, err
+}
+
+// ExtractReader tries to extract the title of the pdf in r
+// with the default configuration.
+func ExtractReader(r io.ReaderAt, size int64) (string, error) {
+	return New().ExtractReader(r, size)
+}
+
+// ExtractReader tries to extract the title of the pdf in r,
+// which is size bytes long. If the pdf must be transformed,
+// the decoder receives a temporary copy of it.
+func (e *Extractor) ExtractReader(r io.ReaderAt, size int64) (tl string, err error) {
+	ctx := context.Background()
+	err = e.scanReader(ctx, r, size, func(docgen func() (*pdf.Reader, error)) error {
+		res, serr := e.titleOfDoc(ctx, docgen)
+		tl = res.Title
+		return serr
+	})
+	return
+}
+
+// ExtractFS tries to extract the title of the pdf file name of fsys.
+// Files that are not an io.ReaderAt, like the files of zip archives,
+// are read in memory.
+func (e *Extractor) ExtractFS(fsys fs.FS, name string) (string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if r, ok := f.(io.ReaderAt); ok {
+		fi, err := f.Stat()
+		if err != nil {
+			return "", err
+		}
+		return e.ExtractReader(r, fi.Size())
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	return e.ExtractReader(bytes.NewReader(data), int64(len(data)))
+}
+
+// titleOfDoc tries to extract the title of document
+// and where it was found.
+func (e *Extractor) titleOfDoc(ctx context.Context, docgen func() (*pdf.Reader, error)) (res Result, rerr error) {
+	defer recoverReader(&rerr)
+
+	doc, err := docgen()
+	if err != nil {
+		return Result{}, readerError(err)
+	}
+	e = e.profiled(doc)
+	res.Pages = doc.NumPage()
+	res.Profile = e.profile
+
+	phrases, perr := e.phrasesOfReader(ctx, doc)
+	if perr != nil && !((e.useDests || e.useInfo || e.useXMP || e.useOutline || e.useTags) && isNoText(perr)) {
+		return res, perr
+	}
+	res.Phrases = len(phrases)
+	for _, p := range phrases {
+		res.TextRuns += p.runs
+	}
+	res.Author = e.authorOfDoc(doc)
+	phrases = append(phrases, e.metaPhrases(doc)...)
+
+	if e.findAbstract {
+		if res.Abstract, err = e.abstractOf(doc); err != nil {
+			return res, err
+		}
+	}
+	if e.findIDs {
+		if err := e.idsOf(doc, &res); err != nil {
+			return res, err
+		}
+	}
+	if e.findYear {
+		if res.Year, err = e.yearOfDoc(doc); err != nil {
+			return res, err
+		}
+	}
+	if e.findVenue {
+		if res.Venue, err = e.venueOfDoc(doc); err != nil {
+			return res, err
+		}
+	}
+	if e.findKeywords {
+		if res.Keywords, err = e.keywordsOf(doc); err != nil {
+			return res, err
+		}
+	}
+	tl, tp := e.titleAndPhrase(phrases)
+	if tp != nil && tp.source == "" && e.isGarbled(tl) {
+		e.logger.Debug("rejected title", "text", tl, "reason", "garbled text")
+		return res, ErrGarbled
+	}
+	if tp != nil {
+		res.Title = tl
+		res.Page = tp.page
+		res.Font = tp.font
+		res.FontSize = tp.fontSize
+		res.BBox = tp.bbox()
+		res.Position = tp.position()
+		if i := slices.Index(phrases, tp); i >= 0 {
+			res.Confidence = confidence(e.candidatesOf(phrases), i)
+		}
+		sp := e.subtitleOf(phrases, tp)
+		if sp != nil {
+			res.Subtitle = sp.String()
+			if e.subtitleSep != "" {
+				res.Title = truncate(withSubtitle(res.Title, e.cased(res.Subtitle), e.subtitleSep), e.maxLen)
+			}
+		}
+		if e.findAuthors {
+			res.Authors = e.authorsOf(phrases, tp, sp)
+		}
+		return res, nil
+	}
+	if tl == "" && e.useDests {
+		labels, err := e.destLabelsOfDoc(docgen)
+		if err != nil {
+			return res, err
+		}
+		if res.Title = e.titleFromLabels(labels); res.Title != "" {
+			return res, nil
+		}
+	}
+	return res, perr
+}
+
+// Candidate is a phrase of the title page with the features
+// that may help to decide whether it is the title.
+type Candidate struct {
+	Index     int        `json:"index"`
+	Text      string     `json:"text"`
+	Font      string     `json:"font"`
+	FontSize  float64    `json:"font_size"`
+	Bold      bool       `json:"bold"`
+	Weight    int        `json:"weight"`
+	BBox      [4]float64 `json:"bbox"`
+	YFraction float64    `json:"y_fraction"`
+	Centered  bool       `json:"centered"`
+	Width     float64    `json:"width"`
+	DictRatio float64    `json:"dict_ratio"`
+	Words     int        `json:"words"`
+	CapsRatio float64    `json:"caps_ratio"`
+
+	// Repeated is true if the phrase repeats at the same
+	// position on the following pages, like a running header.
+	Repeated bool `json:"repeated"`
+
+	// Source is where the candidate comes from, SourcePage for the
+	// text of the page, SourceInfo for the document Info dictionary,
+	// SourceXMP for the XMP metadata, SourceOutline for the bookmarks
+	// and SourceTags for the structure tree of tagged pdfs.
+	Source string `json:"source"`
+
+	// Score is the confidence, from 0 to 1, that the
+	// candidate is the title. See Ranked.
+	Score float64 `json:"score"`
+}
+
+// Candidates returns the phrases of the title page of path,
+// in reading order, as title candidates.
+func (e *Extractor) Candidates(path string) (cands []Candidate, err error) {
+	err = e.scanDoc(context.Background(), path, func(docgen func() (*pdf.Reader, error)) error {
+		phrases, err := e.phrasesOfDoc(context.Background(), docgen)
+		if err != nil {
+			return err
+		}
+		cands = e.candidatesOf(phrases)
+		return nil
+	})
+	return
+}
+
+// candidatesOf returns phrases as scored candidates.
+func (e *Extractor) candidatesOf(phrases []*phrase) []Candidate {
+	var cands []Candidate
+	for i, p := range phrases {
+		s := p.String()
+		ratio, nwords := e.dictRatio(s)
+		c := Candidate{
+			Index:     i,
+			Text:      s,
+			Font:      p.font,
+			FontSize:  p.fontSize,
+			Bold:      isBold(p.font),
+			Weight:    fontWeight(p.font),
+			BBox:      p.bbox(),
+			Width:     p.maxx - p.minx,
+			DictRatio: ratio,
+			Words:     nwords,
+			CapsRatio: capsRatio(s),
+			Repeated:  p.repeated,
+			Source:    cmp.Or(p.source, SourcePage),
+		}
+		if pos := p.position(); pos != nil {
+			c.YFraction = pos.YFraction
+			c.Centered = pos.Centered
+		}
+		cands = append(cands, c)
+	}
+	scoreCandidates(cands, e.weights)
+	return cands
+}
+
+// Ranked returns the candidates of path sorted by decreasing score,
+// or ranked by the scorer of WithScorer, so that callers can apply
+// their own selection policy.
+func (e *Extractor) Ranked(path string) ([]Candidate, error) {
+	cands, err := e.Candidates(path)
+	if e.scorer != nil {
+		return e.scorer.Rank(cands), err
+	}
+	return ByScore.Rank(cands), err
+}
+
+// scoreCandidates sets the scores of cands with the weights w. The score
+// favors large fonts relative to the largest font of the page, phrases
+// in the top third of the page, centered and bold phrases, phrases with
+// many dictionary words and phrases of a few words. Very short phrases and
+// phrases without words, like big initial letters and logos, get half the score. Metadata titles score
+// by their dictionary words and a phrase of the page with the same text
+// as a metadata title gets a bonus, shared by the metadata title.
+func scoreCandidates(cands []Candidate, w Weights) {
+	var largest float64
+	for _, c := range cands {
+		largest = max(largest, c.FontSize)
+	}
+	for i := range cands {
+		c := &cands[i]
+		switch {
+		case c.Source != SourcePage:
+			c.Score = 0.4 + 0.4*c.DictRatio
+		default:
+			c.Score = w.score(*c, largest)
+			if len(c.Text) < 4 || c.Words == 0 {
+				c.Score /= 2
+			}
+		}
+	}
+	for i := range cands {
+		m := &cands[i]
+		if m.Source == SourcePage {
+			continue
+		}
+		for j := range cands {
+			c := &cands[j]
+			if c.Source == SourcePage && agrees(c.Text, m.Text, m.Source) {
+				c.Score = min(1, c.Score+0.2)
+				m.Score = max(m.Score, c.Score)
+			}
+		}
+	}
+	for i := range cands {
+		cands[i].Score = math.Round(cands[i].Score*1000) / 1000
+	}
+}
+
+// Trial is the title extracted with a spacing coefficient.
+type Trial struct {
+	Spacing float64
+	Title   string
+}
+
+// Tune extracts the title of path with spacing coefficients
+// from 0.08 to 0.30 so that users can choose the best for their documents.
+func (e *Extractor) Tune(path string) (trials []Trial, err error) {
+	ctx := context.Background()
+	err = e.scanDoc(ctx, path, func(docgen func() (*pdf.Reader, error)) error {
+		trials = nil
+		// the profile is resolved once, so that
+		// it does not override the spacing of trials.
+		pe, err := e.profiledDoc(docgen)
+		if err != nil {
+			return err
+		}
+		for i := 0; i <= 11; i++ {
+			t := *pe
+			t.profile = ""
+			t.spacing = 0.08 + 0.02*float64(i)
+			phrases, err := t.phrasesOfDoc(ctx, docgen)
+			if err != nil {
+				return err
+			}
+			trials = append(trials, Trial{t.spacing, t.titleFromPhrases(phrases)})
+		}
+		return nil
+	})
+	return
+}
+
+// Document is a document bundled in a pdf along with other documents.
+type Document struct {
+	Page  int    `json:"page"`
+	Title string `json:"title"`
+	*Position
+}
+
+// Documents tries to extract the titles of the documents bundled in path.
+// A document starts at a page with a large title near the top.
+func (e *Extractor) Documents(path string) (docs []Document, err error) {
+	ctx := context.Background()
+	err = e.scanDoc(ctx, path, func(docgen func() (*pdf.Reader, error)) error {
+		var err error
+		docs, err = e.documentsOfDoc(ctx, docgen)
+		return err
+	})
+	return
+}
+
+// scanDoc calls scan with a builder func for the pdf reader of fname.
+// If the pdf package cannot read the file, scan is called again
+// with a builder for a copy transformed by the decoder.
+func (e *Extractor) scanDoc(ctx context.Context, fname string, scan func(docgen func() (*pdf.Reader, error)) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := scan(func() (*pdf.Reader, error) {
+		return pdf.Open(fname)
+	})
+	if err == nil || !e.mustDecode(err) {
+		return err
+	}
+	return decodedError(err, e.scanDecoded(ctx, fname, scan))
+}
+
+// decodedError returns the error of scanning a decoded pdf, derr, or the
+// error of scanning the pdf, err, if it is garbled text. Decoding only
+// may fix garbled text and the reason the file failed is the text.
+func decodedError(err, derr error) error {
+	if derr != nil && errors.Is(err, ErrGarbled) {
+		return err
+	}
+	return derr
+}
+
+// scanReader is like scanDoc but for the pdf in r. The decoder
+// receives a temporary copy of r.
+func (e *Extractor) scanReader(ctx context.Context, r io.ReaderAt, size int64, scan func(docgen func() (*pdf.Reader, error)) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	serr := scan(func() (*pdf.Reader, error) {
+		return pdf.NewReader(r, size)
+	})
+	if serr == nil || !e.mustDecode(serr) {
+		return serr
+	}
+
+	f, err := os.CreateTemp("", "pdftitle-*.pdf")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = io.Copy(f, io.NewSectionReader(r, 0, size))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return decodedError(serr, e.scanDecoded(ctx, f.Name(), scan))
+}
+
+// mustDecode returns true if err is a pdf reader error
+// that the decoder can solve.
+func (e *Extractor) mustDecode(err error) bool {
+	// the pdf package cannot read zipped deflated encoded pdf
+	// so we use the decoder, usually gs, to convert.
+	// garbled text may be of fonts the decoder rewrites with
+	// the right Unicode maps.
+	return e.decoder != nil && (strings.Contains(err.Error(), "stream not present") || errors.Is(err, ErrGarbled))
+}
+
+// scanDecoded calls scan with a builder func for the pdf reader
+// of fname transformed by the decoder.
+func (e *Extractor) scanDecoded(ctx context.Context, fname string, scan func(docgen func() (*pdf.Reader, error)) error) error {
+	e.logger.Debug("decoding pdf", "path", fname)
+	decoder := e.decoder
+	if gs, ok := decoder.(Ghostscript); ok {
+		if gs.MaxSize == 0 {
+			gs.MaxSize = e.limits.MaxDecodedSize
+		}
+		if gs.LastPage == 0 {
+			gs.LastPage = e.lastPage()
+		}
+		decoder = gs
+	}
+	pdfdec, size, err := decoder.Decode(ctx, fname)
+	if err != nil {
+		return err
+	}
+	if n := e.limits.MaxDecodedSize; n > 0 && size > n {
+		return fmt.Errorf("decoded %q is %d bytes, over the limit of %d", fname, size, n)
+	}
+	e.logger.Debug("decoded pdf", "path", fname, "size", size)
+
+	return scan(func() (*pdf.Reader, error) {
+		return pdf.NewReader(pdfdec, size)
+	})
+}
+
+// recoverReader turns a pdf reader panic into an error stored in rerr.
+// It must be called directly by a deferred statement.
+func recoverReader(rerr *error) {
+	if val := recover(); val != nil {
+		// do not send garbage to output
+		var errStr string
+		if err, ok := val.(error); ok {
+			errStr = err.Error()
+		} else {
+			errStr = fmt.Sprint(val)
+		}
+		if i := strings.Index(errStr, "malformed hex string"); i >= 0 {
+			*rerr = fmt.Errorf("%w: reader paniced: malformed hex string", ErrMalformed)
+		} else {
+			*rerr = fmt.Errorf("%w: reader paniced: %s", ErrMalformed, errStr)
+		}
+	}
+}
+
+// phrasesOfDoc extracts the phrases of document.
+// We pass the document with a builder func to handle pdf reader
+// panics in one place.
+func (e *Extractor) phrasesOfDoc(ctx context.Context, docgen func() (*pdf.Reader, error)) (phrases []*phrase, rerr error) {
+	defer recoverReader(&rerr)
+
+	doc, err := docgen()
+	if err != nil {
+		return nil, readerError(err)
+	}
+	e = e.profiled(doc)
+	phrases, err = e.phrasesOfReader(ctx, doc)
+	if err != nil && !isNoText(err) {
+		return nil, err
+	}
+	phrases = append(phrases, e.metaPhrases(doc)...)
+	if len(phrases) == 0 {
+		return nil, err
+	}
+	return phrases, nil
+}
+
+// phrasesOfReader is like phrasesOfDoc for an open document.
+func (e *Extractor) phrasesOfReader(ctx context.Context, doc *pdf.Reader) ([]*phrase, error) {
+	page, num, err := e.titlePage(doc)
+	if err != nil {
+		return nil, err
+	}
+	if page.V.IsNull() {
+		return nil, ErrNoText
+	}
+
+	// the phrases of the title page and the pages that follow it
+	// up to the number of pages set with WithPages, or only the
+	// page set with WithPage.
+	pages := max(1, e.pages)
+	if e.pageNum > 0 {
+		pages = 1
+	}
+	var phrases []*phrase
+	for i, n := num, 0; i <= e.numPage(doc) && n < pages; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		p := doc.Page(i)
+		if p.V.IsNull() {
+			continue
+		}
+		n++
+		pagePhrases := e.phrasesOfPage(p)
+		for _, p := range pagePhrases {
+			p.page = i
+		}
+		e.logger.Debug("phrases of page", "page", i, "count", len(pagePhrases))
+		if e.repeatHeaderStrip || e.repeatHeaderTitle {
+			if err := e.markRepeated(ctx, doc, i, pagePhrases); err != nil {
+				return nil, err
+			}
+		}
+		phrases = append(phrases, pagePhrases...)
+	}
+	if len(phrases) == 0 {
+		return nil, noTextError(page)
+	}
+	if e.repeatHeaderStrip {
+		phrases = slices.DeleteFunc(phrases, func(p *phrase) bool {
+			return p.repeated
+		})
+	}
+	phrases = slices.DeleteFunc(phrases, func(p *phrase) bool {
+		reason := e.excluded(p)
+		if reason != "" {
+			e.logger.Debug("rejected phrase", "text", p.String(), "reason", reason)
+		}
+		return reason != ""
+	})
+	if len(phrases) == 0 {
+		return nil, nil
+	}
+	return phrases, nil
+}
+
+// titlePage returns the page of doc with the title and its number.
+// This is the page set with WithPage or else the first page.
+func (e *Extractor) titlePage(doc *pdf.Reader) (pdf.Page, int, error) {
+	if e.pageNum <= 0 {
+		if e.skipCovers {
+			if page, num := e.firstTextPage(doc); num > 0 {
+				return page, num, nil
+			}
+		}
+		page, num := e.firstPage(doc)
+		return page, num, nil
+	}
+	if n := e.numPage(doc); e.pageNum > n {
+		return pdf.Page{}, 0, fmt.Errorf("page %d out of range: document has %d pages", e.pageNum, n)
+	}
+	page := doc.Page(e.pageNum)
+	if page.V.IsNull() {
+		return pdf.Page{}, 0, fmt.Errorf("page %d not found", e.pageNum)
+	}
+	return page, e.pageNum, nil
+}
+
+// maxCoverPages is the maximum number of pages searched for
+// the first page with text, skipping covers and blank pages.
+const maxCoverPages = 5
+
+// minTextLetters is the minimum number of letters of a page
+// that is not a cover or a blank page.
+const minTextLetters = 16
+
+// firstTextPage returns the first page of doc, among the first
+// maxCoverPages, with some text and its number. Blank pages and covers
+// with only images or a few words, like publisher splash pages, are
+// skipped. The number is 0 if there is no such page.
+func (e *Extractor) firstTextPage(doc *pdf.Reader) (pdf.Page, int) {
+	for i := 1; i <= min(e.numPage(doc), maxCoverPages); i++ {
+		p := doc.Page(i)
+		if p.V.IsNull() {
+			continue
+		}
+		letters := 0
+		for _, t := range p.Content().Text {
+			for _, r := range t.S {
+				if unicode.IsLetter(r) {
+					letters++
+				}
+			}
+		}
+		if letters >= minTextLetters {
+			return p, i
+		}
+		e.logger.Debug("skipped page", "page", i, "reason", "cover or blank page", "letters", letters)
+	}
+	return pdf.Page{}, 0
+}
+
+// firstPage returns the first non null page of doc and its number.
+func (e *Extractor) firstPage(doc *pdf.Reader) (pdf.Page, int) {
+	for i := 1; i <= e.numPage(doc); i++ {
+		if p := doc.Page(i); !p.V.IsNull() {
+			return p, i
+		}
+	}
+	return pdf.Page{}, 0
+}
+
+// markRepeated marks the phrases of page num that are repeated at
+// the same position on the next few pages, or the pages before it near
+// the end of doc, like running headers and journal banners. The texts
+// are compared ignoring case, punctuation and numbers, like page numbers
+// and volumes, and the positions within half the font size, since
+// running headers of odd and even pages are often set a little apart.
+func (e *Extractor) markRepeated(ctx context.Context, doc *pdf.Reader, num int, phrases []*phrase) error {
+	key := func(p *phrase) string {
+		return strings.Join(strings.FieldsFunc(normalized(p.String()), func(r rune) bool {
+			return r == ' ' || unicode.IsDigit(r)
+		}), " ")
+	}
+
+	// the positions of the phrases of the other pages by their text.
+	seen := make(map[string][]float64)
+	add := func(i int) {
+		page := doc.Page(i)
+		if page.V.IsNull() {
+			return
+		}
+		for _, p := range e.phrasesOfPage(page) {
+			if k := key(p); k != "" {
+				seen[k] = append(seen[k], p.y)
+			}
+		}
+	}
+	n := 0
+	for i := num + 1; i <= e.numPage(doc) && n < repeatPages; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		add(i)
+		n++
+	}
+	for i := num - 1; i >= 1 && n < repeatPages; i-- {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		add(i)
+		n++
+	}
+	for _, p := range phrases {
+		p.repeated = slices.ContainsFunc(seen[key(p)], func(y float64) bool {
+			return math.Abs(y-p.y) <= max(2, p.fontSize/2)
+		})
+	}
+	return nil
+}
+
+// repeatPages is the number of pages compared
+// with a page to find its running headers.
+const repeatPages = 3
+
+// phrasesOfPage extracts the phrases of page in reading order.
+func (e *Extractor) phrasesOfPage(page pdf.Page) (phrases []*phrase) {
+	text := page.Content().Text
+	if n := e.limits.MaxTextRuns; n > 0 && len(text) > n {
+		e.logger.Debug("text runs limit reached", "runs", len(text), "limit", n)
+		text = text[:n]
+	}
+	text = withoutShadows(text)
+
+	text, orients, major := orientText(page, text)
+
+	var currPhrase *phrase
+	order, cols := columnOrder(text, orients)
+	for k, i := range order {
+		t := text[i]
+		newColumn := k > 0 && cols[order[k-1]] != cols[i]
+		if currPhrase == nil || newColumn || currPhrase.orient != orients[i] || !currPhrase.tryAppend(t) {
+			if currPhrase != nil {
+				phrases = append(phrases, currPhrase)
+			}
+			currPhrase = newPhrase(t, e.spacing, e.sizeTolerance, e.limits.MaxPhraseLen)
+			currPhrase.orient = orients[i]
+		}
+	}
+	if currPhrase != nil {
+		phrases = append(phrases, currPhrase)
+	}
+	for _, p := range phrases {
+		p.stripMarks()
+		p.joinLetters()
+	}
+	phrases = mergeLines(phrases)
+
+	box := pageBox(page)
+	for _, p := range phrases {
+		p.normalize()
+		e.unshift(p)
+		e.dehyphenate(p)
+		p.box = p.orient.rect(box)
+		p.rotated = p.orient != major
+		p.trunc = e.maxLen
+	}
+	return
+}
+
+// destLabelsOfDoc returns the labels of the link annotations
+// of the first page of document, largest first, followed by
+// the names of the destinations of the document.
+func (e *Extractor) destLabelsOfDoc(docgen func() (*pdf.Reader, error)) (labels []string, rerr error) {
+	defer recoverReader(&rerr)
+
+	doc, err := docgen()
+	if err != nil {
+		return nil, readerError(err)
+	}
+
+	type link struct {
+		label string
+		area  float64
+	}
+	var links []link
+	page, _, err := e.titlePage(doc)
+	if err != nil {
+		return nil, err
+	}
+	annots := page.V.Key("Annots")
+	for i := 0; i < annots.Len(); i++ {
+		a := annots.Index(i)
+		if a.Key("Subtype").Name() != "Link" {
+			continue
+		}
+		label := a.Key("T").Text()
+		if label == "" {
+			label = a.Key("Contents").Text()
+		}
+		r := a.Key("Rect")
+		area := (r.Index(2).Float64() - r.Index(0).Float64()) * (r.Index(3).Float64() - r.Index(1).Float64())
+		links = append(links, link{label, math.Abs(area)})
+	}
+	slices.SortStableFunc(links, func(a, b link) int {
+		return cmp.Compare(b.area, a.area)
+	})
+	for _, l := range links {
+		labels = append(labels, l.label)
+	}
+
+	root := doc.Trailer().Key("Root")
+	labels = append(labels, root.Key("Dests").Keys()...)
+	labels = appendNames(labels, root.Key("Names").Key("Dests"))
+	return
+}
+
+// appendNames appends the keys of the name tree node to names.
+func appendNames(names []string, node pdf.Value) []string {
+	kv := node.Key("Names")
+	for i := 0; i < kv.Len(); i += 2 {
+		names = append(names, kv.Index(i).Text())
+	}
+	kids := node.Key("Kids")
+	for i := 0; i < kids.Len(); i++ {
+		names = appendNames(names, kids.Index(i))
+	}
+	return names
+}
+
+// titleFromLabels returns the first of labels that looks like a title.
+// Labels are often identifiers like "section.1" so a title
+// must have at least two words.
+func (e *Extractor) titleFromLabels(labels []string) string {
+	for _, l := range labels {
+		tl := strings.Join(strings.Fields(printable(l)), " ")
+		if len(wordsExtractor.FindAllString(tl, 2)) < 2 {
+			continue
+		}
+		if e.valid(tl) {
+			return truncate(tl, e.maxLen)
+		}
+	}
+	return ""
+}
+
+// documentsOfDoc scans all pages of document for pages that start
+// a new document and returns their titles.
+func (e *Extractor) documentsOfDoc(ctx context.Context, docgen func() (*pdf.Reader, error)) (docs []Document, rerr error) {
+	defer recoverReader(&rerr)
+
+	doc, err := docgen()
+	if err != nil {
+		return nil, readerError(err)
+	}
+
+	for i := 1; i <= e.numPage(doc); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page := doc.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		phrases := e.phrasesOfPage(page)
+		if !startsDocument(page, phrases) {
+			continue
+		}
+		if tl, tp := e.titleAndPhrase(phrases); tl != "" {
+			docs = append(docs, Document{Page: i, Title: tl, Position: tp.position()})
+		}
+	}
+	return
+}
+
+// startsDocument returns true if the page looks like the first page
+// of a document. That is, its largest phrase is in the top third of
+// the page and it is clearly larger than the body text.
+func startsDocument(page pdf.Page, phrases []*phrase) bool {
+	if len(phrases) == 0 {
+		return false
+	}
+
+	// the body text is the font size with the most letters
+	letters := make(map[float64]int)
+	for _, p := range phrases {
+		letters[p.fontSize] += p.length
+	}
+	var bodySize float64
+	for siz, n := range letters {
+		if n > letters[bodySize] {
+			bodySize = siz
+		}
+	}
+
+	largest := slices.MaxFunc(phrases, func(a, b *phrase) int {
+		return cmp.Compare(a.fontSize, b.fontSize)
+	})
+	box := pageBox(page)
+	return largest.fontSize >= 1.5*bodySize &&
+		largest.y >= box.Min.Y+2*(box.Max.Y-box.Min.Y)/3
+}
+
+// pageBox returns the MediaBox of page.
+// If the page has no MediaBox it assumes a US letter page.
+func pageBox(page pdf.Page) pdf.Rect {
+	for v := page.V; !v.IsNull(); v = v.Key("Parent") {
+		if mb := v.Key("MediaBox"); mb.Len() == 4 {
+			return pdf.Rect{
+				Min: pdf.Point{X: mb.Index(0).Float64(), Y: mb.Index(1).Float64()},
+				Max: pdf.Point{X: mb.Index(2).Float64(), Y: mb.Index(3).Float64()},
+			}
+		}
+	}
+	return pdf.Rect{Max: pdf.Point{X: 612, Y: 792}}
+}
+
+// titleFromPhrases tries to guess which of the phrases is the document title.
+func (e *Extractor) titleFromPhrases(phrases []*phrase) string {
+	tl, _ := e.titleAndPhrase(phrases)
+	return tl
+}
+
+// titleAndPhrase is like titleFromPhrases but also returns
+// the phrase of the title.
+func (e *Extractor) titleAndPhrase(phrases []*phrase) (string, *phrase) {
+	e.logPhrases(phrases)
+	if e.minWords > 0 {
+		phrases = slices.DeleteFunc(slices.Clone(phrases), func(p *phrase) bool {
+			if len(wordsExtractor.FindAllString(p.String(), e.minWords)) < e.minWords {
+				e.logger.Debug("rejected phrase", "text", p.String(), "reason", fmt.Sprintf("fewer than %d words", e.minWords))
+				return true
+			}
+			return false
+		})
+	}
+
+	var tp *phrase
+	if tp = taggedPhrase(phrases); tp != nil {
+		e.logger.Debug("tagged title", "title", tp.String())
+	} else if e.scorer != nil {
+		tp = e.bestRanked(phrases)
+	} else if tp = agreedPhrase(phrases); tp == nil {
+		tp = e.bestScored(phrases)
+	}
+	if tp == nil {
+		e.logger.Debug("no title candidate")
+		return "", nil
+	}
+	tl := tp.String()
+	e.logger.Debug("title candidate", "title", tl, "font", tp.font, "size", tp.fontSize)
+
+	if e.stripQuotes {
+		tl = unquoted(tl)
+	}
+	tl = e.cased(tl)
+
+	if e.valid(tl) {
+		return tl, tp
+	}
+	return "", nil
+}
+
+// logPhrases logs the phrases with the largest fonts, the candidates
+// for the title, and with LevelTrace all the phrases.
+func (e *Extractor) logPhrases(phrases []*phrase) {
+	ctx := context.Background()
+	if !e.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	top := slices.SortedStableFunc(slices.Values(phrases), func(a, b *phrase) int {
+		return cmp.Compare(b.fontSize, a.fontSize)
+	})
+	n := 5
+	if e.logger.Enabled(ctx, LevelTrace) {
+		n = len(top)
+	}
+	for i, p := range top[:min(n, len(top))] {
+		ratio, _ := e.dictRatio(p.String())
+		attrs := []any{"rank", i + 1, "text", p.String(), "font", p.font, "size", p.fontSize, "dict_ratio", math.Round(ratio*100) / 100}
+		if pos := p.position(); pos != nil {
+			attrs = append(attrs, "y_fraction", pos.YFraction, "centered", pos.Centered)
+		}
+		e.logger.Debug("phrase", attrs...)
+	}
+}
+
+// bestScored returns the phrase of the page with the best score with
+// the weights of e, see scoreCandidates, or the first metadata phrase
+// if the page has no phrases. Ties go to the larger and then the bolder
+// font. With WithRepeatHeaderTitle the phrases repeated on the next
+// pages, like running headers, come first.
+func (e *Extractor) bestScored(phrases []*phrase) *phrase {
+	cands := slices.DeleteFunc(e.candidatesOf(phrases), func(c Candidate) bool {
+		return c.Source != SourcePage
+	})
+	slices.SortStableFunc(cands, func(a, b Candidate) int {
+		if e.repeatHeaderTitle && a.Repeated != b.Repeated {
+			if a.Repeated {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			cmp.Compare(b.FontSize, a.FontSize),
+			cmp.Compare(b.Weight, a.Weight),
+		)
+	})
+	if len(cands) > 0 {
+		return phrases[cands[0].Index]
+	}
+	for _, p := range phrases {
+		if p.source != "" {
+			return p
+		}
+	}
+	return nil
+}
+
+// bestRanked returns the phrase of the first candidate
+// ranked by the scorer of e.
+func (e *Extractor) bestRanked(phrases []*phrase) *phrase {
+	ranked := e.scorer.Rank(e.candidatesOf(phrases))
+	if len(ranked) == 0 {
+		return nil
+	}
+	if i := ranked[0].Index; i >= 0 && i < len(phrases) {
+		return phrases[i]
+	}
+	return nil
+}
+
+// unquoted returns s without the quotation marks that enclose it.
+// Quotation marks that enclose only part of s are kept.
+func unquoted(s string) string {
+	for _, pair := range quotePairs {
+		q := []rune(pair)
+		first, n := utf8.DecodeRuneInString(s)
+		last, m := utf8.DecodeLastRuneInString(s)
+		if first != q[0] || last != q[1] || len(s) < n+m {
+			continue
+		}
+		inner := s[n : len(s)-m]
+		if encloses(inner, q[0], q[1]) {
+			return strings.TrimSpace(inner)
+		}
+	}
+	return s
+}
+
+// encloses returns true if the quotation marks open and close
+// around inner enclose it all. For example in "a" and "b"
+// the outer marks do not enclose the whole string.
+func encloses(inner string, open, close rune) bool {
+	if open == close {
+		return !strings.ContainsRune(inner, open)
+	}
+	depth := 0
+	for _, r := range inner {
+		switch r {
+		case open:
+			depth++
+		case close:
+			if depth--; depth < 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
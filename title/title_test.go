@@ -0,0 +1,39 @@
+package title
+
+import "testing"
+
+func TestUnquoted(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"“Toward a Theory of Types”", "Toward a Theory of Types"},
+		{"‘Toward a Theory of Types’", "Toward a Theory of Types"},
+		{"«Vers une théorie des types»", "Vers une théorie des types"},
+		{"« Vers une théorie des types »", "Vers une théorie des types"},
+		{"„Zur Theorie der Typen“", "Zur Theorie der Typen"},
+		{`"Toward a Theory of Types"`, "Toward a Theory of Types"},
+		{"「型の理論」", "型の理論"},
+
+		// unbalanced quotes are kept.
+		{"“Toward a Theory of Types", "“Toward a Theory of Types"},
+		{"Toward a Theory of Types”", "Toward a Theory of Types”"},
+		{"“Toward a Theory of Types’", "“Toward a Theory of Types’"},
+		{"«Vers une théorie des types", "«Vers une théorie des types"},
+
+		// quotes inside the title survive.
+		{"The “Worse is Better” Argument", "The “Worse is Better” Argument"},
+		{"“Worse” is “Better”", "“Worse” is “Better”"},
+		{"“On “Worse is Better””", "On “Worse is Better”"},
+		{`"Worse" and "Better"`, `"Worse" and "Better"`},
+		{"«Le «pire» est mieux»", "Le «pire» est mieux"},
+		{"Shakespeare’s Sonnets", "Shakespeare’s Sonnets"},
+
+		{"", ""},
+		{"“”", ""},
+	}
+	for _, tt := range tests {
+		if got := unquoted(tt.s); got != tt.want {
+			t.Errorf("unquoted(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
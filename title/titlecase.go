@@ -0,0 +1,125 @@
+package title
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+)
+
+// smallWords are the words title case keeps in lower case unless
+// they start or end the title or a subtitle, the articles, conjunctions
+// and prepositions of languages by their code, see localeCode.
+var smallWords = map[string]map[string]bool{
+	english: wordSet("a an and as at but by for from in into nor of on or the to via vs with"),
+	"de":    wordSet("der die das den dem des ein eine einer eines einem einen und oder aber von vom zu zum zur im in am an auf aus bei mit nach für über unter durch gegen ohne um als wie"),
+	"fr":    wordSet("le la les un une des du de et ou à au aux en dans par pour sur sous avec sans entre vers"),
+	"es":    wordSet("el la los las un una unos unas y e o u de del a al en con por para sin sobre entre"),
+	"it":    wordSet("il lo la i gli le un uno una e o di del della dei delle a al alla in nel nella con per su da tra fra"),
+	"pt":    wordSet("o a os as um uma e ou de do da dos das em no na nos nas com por para sem sobre entre"),
+	"nl":    wordSet("de het een en of van in op aan met voor door bij naar uit over tot om"),
+}
+
+// wordSet returns the words of s, separated by spaces, as a set.
+func wordSet(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(s) {
+		set[w] = true
+	}
+	return set
+}
+
+// wordRuns are the runs of letters and digits of a title.
+var wordRuns = regexp.MustCompile(`[\p{L}\p{M}\p{N}]+`)
+
+// Cases of titles, see WithCase.
+const (
+	// CasePreserve keeps the case of titles.
+	CasePreserve = "preserve"
+
+	// CaseTitle capitalizes the words of titles, except the small
+	// words, like "A Theory of Systems".
+	CaseTitle = "title"
+
+	// CaseSentence capitalizes the first words of titles and of
+	// subtitles and names, like "A theory of Byzantine systems".
+	CaseSentence = "sentence"
+)
+
+// cased returns tl in the case of WithCase, or in title case if
+// it is in all caps and WithTitleCase is set, or else tl.
+func (e *Extractor) cased(tl string) string {
+	switch {
+	case e.textCase != "" && e.textCase != CasePreserve:
+		return e.recased(tl, e.textCase)
+	case e.titleCase && capsRatio(tl) >= 0.9:
+		return e.recased(tl, CaseTitle)
+	}
+	return tl
+}
+
+// recased returns tl in case c, CaseTitle or CaseSentence. The small
+// words of title case are those of the language of the locale, and
+// there are none for languages without a list. Acronyms,
+// words with digits and, in titles in all caps, short words that are not
+// dictionary words, probably acronyms too, keep their case. In titles not
+// in all caps, words in all caps and words with capitals inside, like
+// LaTeX, keep their case too, and in sentence case capitalized words
+// that are not dictionary words, probably names, stay capitalized.
+func (e *Extractor) recased(tl, c string) string {
+	allCaps := capsRatio(tl) >= 0.9
+	lower := cases.Lower(e.locale)
+	title := cases.Title(e.locale)
+	small := smallWords[e.localeCode()]
+
+	var b strings.Builder
+	locs := wordRuns.FindAllStringIndex(tl, -1)
+	prev := 0
+	for i, loc := range locs {
+		sep, w := tl[prev:loc[0]], tl[loc[0]:loc[1]]
+		b.WriteString(sep)
+		prev = loc[1]
+
+		lw := lower.String(w)
+		first := i == 0 || strings.ContainsAny(sep, ":.?!—–")
+		last := i == len(locs)-1
+		switch {
+		case e.acronyms[w] || strings.ContainsFunc(w, unicode.IsDigit):
+			b.WriteString(w)
+		case !allCaps && (isAllCaps(w) || hasInnerCaps(w)):
+			b.WriteString(w)
+		case strings.HasSuffix(sep, "'") || strings.HasSuffix(sep, "’"):
+			// the s of possessives and the t of contractions.
+			b.WriteString(lw)
+		case allCaps && !e.isWord(w) && utf8.RuneCountInString(w) <= 5:
+			b.WriteString(w)
+		case c == CaseSentence && first:
+			b.WriteString(title.String(w))
+		// the stems of plurals are not always dictionary words.
+		case c == CaseSentence && !allCaps && w != lw && !e.isWord(lw) && !e.isWord(strings.TrimSuffix(lw, "s")):
+			b.WriteString(w)
+		case c == CaseSentence:
+			b.WriteString(lw)
+		case small[lw] && !first && !last:
+			b.WriteString(lw)
+		default:
+			b.WriteString(title.String(w))
+		}
+	}
+	b.WriteString(tl[prev:])
+	return b.String()
+}
+
+// isAllCaps returns true if w has at least two letters, all capitals.
+func isAllCaps(w string) bool {
+	return utf8.RuneCountInString(w) >= 2 && !strings.ContainsFunc(w, unicode.IsLower)
+}
+
+// hasInnerCaps returns true if w has capitals after its first letter
+// and lower case letters, like LaTeX or iPhone.
+func hasInnerCaps(w string) bool {
+	_, size := utf8.DecodeRuneInString(w)
+	return strings.ContainsFunc(w[size:], unicode.IsUpper) && strings.ContainsFunc(w, unicode.IsLower)
+}
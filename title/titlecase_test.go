@@ -0,0 +1,35 @@
+package title
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestRecasedLocale(t *testing.T) {
+	tests := []struct {
+		locale language.Tag
+		c      string
+		s      string
+		want   string
+	}{
+		{language.Und, CaseTitle, "a theory of distributed systems", "A Theory of Distributed Systems"},
+		{language.English, CaseTitle, "a theory of distributed systems", "A Theory of Distributed Systems"},
+		{language.Und, CaseTitle, "eine theorie der verteilten systeme", "Eine Theorie Der Verteilten Systeme"},
+		{language.German, CaseTitle, "eine theorie der verteilten systeme", "Eine Theorie der Verteilten Systeme"},
+		{language.German, CaseTitle, "a theory of distributed systems", "A Theory Of Distributed Systems"},
+		{language.French, CaseTitle, "une théorie des systèmes répartis", "Une Théorie des Systèmes Répartis"},
+		{language.Spanish, CaseTitle, "una teoría de los sistemas", "Una Teoría de los Sistemas"},
+		// no small words for languages without a list.
+		{language.Finnish, CaseTitle, "a theory of distributed systems", "A Theory Of Distributed Systems"},
+		{language.Finnish, CaseTitle, "hajautettujen järjestelmien teoria", "Hajautettujen Järjestelmien Teoria"},
+		// the first and the last words are capitalized.
+		{language.German, CaseTitle, "der stand der dinge und", "Der Stand der Dinge Und"},
+	}
+	for _, tt := range tests {
+		e := New(WithLocale(tt.locale))
+		if got := e.recased(tt.s, tt.c); got != tt.want {
+			t.Errorf("recased(%q, %q) with locale %v = %q, want %q", tt.s, tt.c, tt.locale, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package title
+
+// A TitleValidator decides if a string extracted from a pdf is a title.
+// The default validator requires a fraction of the words to be
+// in the embedded dictionary, see WithDictThreshold.
+type TitleValidator interface {
+	Valid(s string) bool
+}
+
+// ValidatorFunc adapts a function to a TitleValidator.
+type ValidatorFunc func(s string) bool
+
+// Valid calls f(s).
+func (f ValidatorFunc) Valid(s string) bool {
+	return f(s)
+}
+
+// A checker is a TitleValidator that may fail, like Hunspell.
+// Titles are not valid if it fails and its errors are logged.
+type checker interface {
+	Check(s string) (bool, error)
+}
+
+// valid returns true if tl passes the validator of e.
+func (e *Extractor) valid(tl string) bool {
+	if e.disableWordsCheck {
+		return true
+	}
+	if c, ok := e.validator.(checker); ok {
+		ok, err := c.Check(tl)
+		if err != nil {
+			e.logger.Warn("rejected title", "text", tl, "reason", "validator failed", "error", err)
+		} else if !ok {
+			e.logger.Debug("rejected title", "text", tl, "reason", "validator")
+		}
+		return ok && err == nil
+	}
+	if e.validator != nil {
+		ok := e.validator.Valid(tl)
+		if !ok {
+			e.logger.Debug("rejected title", "text", tl, "reason", "validator")
+		}
+		return ok
+	}
+	return e.dictCheck(tl)
+}
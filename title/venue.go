@@ -0,0 +1,87 @@
+package title
+
+import (
+	"regexp"
+	"strings"
+
+	"rsc.io/pdf"
+)
+
+// venueName matches the start of journal and conference names, after
+// phrases like In or To appear in, in the first words of a line.
+var venueName = regexp.MustCompile(`(?i)^(?:(?:in|published in|to appear in|accepted (?:at|in|to|for)|appeared in)\s+)?((?:[\pL&.-]+\s+){0,3}?(?:proceedings of|proc\.|transactions on|journal (?:of|on)|annals of|conference on|symposium on|workshop on)\b.*)`)
+
+// venueMarks are the words, in lower case, of names that are only venues.
+// Names with journal of or annals of may be titles, like the title of
+// a book, unless they have a volume or a year.
+var venueMarks = []string{"proceedings", "proc.", "transactions", "conference", "symposium", "workshop"}
+
+// venueNumbers matches the volume, number, year and pages that follow
+// venue names, like Vol. 12, No. 3, 12 (2021) 1-10 or pp. 1-10.
+var venueNumbers = regexp.MustCompile(`(?i)\s*(?:,|\bvol(?:ume)?\.?\s*\d|\bno\.\s*\d|\bpp\.|\bpages\s+\d|\s\d+\s*\(\d{4}\)|\(\d{4}\)|\s\d+\s*[-–]\s*\d+$|\s\d+$)`)
+
+// maxVenueWords is the maximum number of words of venue names.
+const maxVenueWords = 16
+
+// venueOf returns the journal or conference name in the line s,
+// without the volume, the year and the pages, or the empty string
+// if s is not a venue line, like a running header or a banner.
+func venueOf(s string) (venue string, numbered bool) {
+	m := venueName.FindStringSubmatch(s)
+	if m == nil {
+		return "", false
+	}
+	venue = m[1]
+	if loc := venueNumbers.FindStringIndex(venue); loc != nil {
+		venue, numbered = venue[:loc[0]], true
+	}
+	venue = strings.TrimRight(strings.TrimSpace(venue), ".:;-–—")
+	if n := len(strings.Fields(venue)); n < 2 || n > maxVenueWords {
+		return "", false
+	}
+	return venue, numbered
+}
+
+// isVenue returns true if the text s of a phrase is a journal or a
+// conference name, like a banner on the first page of a paper.
+func isVenue(s string) bool {
+	if strings.Contains(s, "\n") {
+		s = s[:strings.Index(s, "\n")]
+	}
+	venue, numbered := venueOf(s)
+	if venue == "" {
+		return false
+	}
+	if numbered {
+		return true
+	}
+	l := strings.ToLower(venue)
+	for _, w := range venueMarks {
+		if strings.Contains(l, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// venueOfDoc returns the journal or conference name of the first
+// line of the phrases of the title page of doc, running headers
+// and footers included, that is a venue.
+func (e *Extractor) venueOfDoc(doc *pdf.Reader) (string, error) {
+	_, num, err := e.titlePage(doc)
+	if isNoText(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	for _, s := range e.textOfPages(doc, num, 1) {
+		for _, line := range strings.Split(s, "\n") {
+			if isVenue(line) {
+				venue, _ := venueOf(line)
+				e.logger.Debug("venue", "venue", venue)
+				return venue, nil
+			}
+		}
+	}
+	return "", nil
+}
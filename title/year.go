@@ -0,0 +1,79 @@
+package title
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"rsc.io/pdf"
+)
+
+// copyrightYear matches the year of copyright notices,
+// like © 2021, (c) 2021 or Copyright 2019-2021 ACM.
+var copyrightYear = regexp.MustCompile(`(?i:©|\(c\)|\bcopyright\b)\s*(?:©\s*|\(c\)\s*)?(?:(?:19|20)\d\d\s*[-–]\s*)?((?:19|20)\d\d)\b`)
+
+// arXivStampYear matches the date of the side stamp of arXiv
+// preprints, like arXiv:2104.12345v2 [cs.DC] 26 Apr 2021.
+var arXivStampYear = regexp.MustCompile(`(?i:\barxiv:)\S+\s+\[[^\]]*\]\s+\d{1,2}\s+\pL{3,}\.?\s+((?:19|20)\d\d)\b`)
+
+// anyYear matches the years in text that are not
+// parts of numbers, dates like 2021-04-26 or DOIs.
+var anyYear = regexp.MustCompile(`(?:^|[^\d./:-])((?:19|20)\d\d)(?:$|[^\d./:-]|\.\D|\.$)`)
+
+// yearOf returns the likely publication year in texts, the text of a
+// title page, or 0 if there is none. The year of the copyright notice
+// is preferred, then the date of the arXiv stamp and the year of the
+// arXiv identifier, then the first year, usually of a header line.
+// Years in the future are ignored, they are not publication years.
+func yearOf(texts []string) int {
+	last := time.Now().Year() + 1
+	var first, arXiv int
+	for _, s := range texts {
+		for _, m := range copyrightYear.FindAllStringSubmatch(s, -1) {
+			if y, _ := strconv.Atoi(m[1]); y <= last {
+				return y
+			}
+		}
+		if m := arXivStampYear.FindStringSubmatch(s); m != nil && arXiv == 0 {
+			if y, _ := strconv.Atoi(m[1]); y <= last {
+				arXiv = y
+			}
+		}
+		if first != 0 {
+			continue
+		}
+		for _, m := range anyYear.FindAllStringSubmatch(s, -1) {
+			if y, _ := strconv.Atoi(m[1]); y <= last {
+				first = y
+				break
+			}
+		}
+	}
+	if arXiv == 0 {
+		// new arXiv identifiers start with the year and month.
+		if id := arXivOf(texts); len(id) > 4 && id[4] == '.' {
+			if yy, err := strconv.Atoi(id[:2]); err == nil {
+				arXiv = 2000 + yy
+			}
+		}
+	}
+	if arXiv != 0 {
+		return arXiv
+	}
+	return first
+}
+
+// yearOfDoc returns the likely publication year of the title page of doc.
+func (e *Extractor) yearOfDoc(doc *pdf.Reader) (int, error) {
+	_, num, err := e.titlePage(doc)
+	if isNoText(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	y := yearOf(e.textOfPages(doc, num, 1))
+	if y != 0 {
+		e.logger.Debug("year", "year", y)
+	}
+	return y, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/anastasop/pdftitle/title"
+)
+
+// runTrain is the train command. It fits a model to the titles
+// of a list of files and writes it for the -model flag.
+func runTrain(args []string) {
+	set := flag.NewFlagSet("train", flag.ExitOnError)
+	addExtractorFlags(set)
+	output := set.String("o", "", "write the model to `file` instead of stdout")
+	set.Usage = commandUsage(set, "train [flags] titles.csv", `Train extracts the title candidates of the files listed in a csv
+file with rows of path and correct title, fits a logistic regression
+model that tells the titles from the other candidates and writes it
+as json. Extract and the other commands use it with -model file.`)
+	parseFlags(set, args)
+	if set.NArg() != 1 {
+		set.Usage()
+	}
+
+	f, err := os.Open(set.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	rows, err := csv.NewReader(f).ReadAll()
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	extractor := newExtractor()
+	var examples []title.Example
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		path, tl := row[0], row[1]
+		cands, err := extractor.Candidates(path)
+		if err != nil && !errors.Is(err, title.ErrNoText) {
+			fmt.Fprintf(os.Stderr, "error: %s: %v\n", path, err)
+			continue
+		}
+		examples = append(examples, title.Example{Candidates: cands, Title: tl})
+	}
+	model, used, err := title.Train(examples)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "trained with %d of %d files, the others have no candidate with their title\n", used, len(examples))
+
+	data, err := json.MarshalIndent(model, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+	if *output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}